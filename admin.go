@@ -8,8 +8,12 @@ package gitness
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"slices"
+	"strconv"
+	"time"
 )
 
 // AdminService handles communication with admin related methods
@@ -24,74 +28,144 @@ type AuditService struct {
 
 // AuditLog represents an audit log entry
 type AuditLog struct {
-	ID                   *int64  `json:"id,omitempty"`
-	Created              *Time   `json:"created,omitempty"`
-	Action               *string `json:"action,omitempty"`
-	ResourceType         *string `json:"resource_type,omitempty"`
-	ResourceIdentifier   *string `json:"resource_identifier,omitempty"`
-	PrincipalUID         *string `json:"principal_uid,omitempty"`
-	PrincipalDisplayName *string `json:"principal_display_name,omitempty"`
-	Data                 *string `json:"data,omitempty"`
+	ID            *int64             `json:"id,omitempty"`
+	EventID       *string            `json:"event_id,omitempty"`
+	Action        *AuditAction       `json:"action,omitempty"`
+	ResourceType  *AuditResourceType `json:"resource_type,omitempty"`
+	ResourceID    *string            `json:"resource_id,omitempty"`
+	SpacePath     *string            `json:"space_path,omitempty"`
+	UserID        *int64             `json:"user_id,omitempty"`
+	UserUID       *string            `json:"user_uid,omitempty"`
+	UserName      *string            `json:"user_name,omitempty"`
+	UserEmail     *string            `json:"user_email,omitempty"`
+	ClientIP      *string            `json:"client_ip,omitempty"`
+	RequestMethod *string            `json:"request_method,omitempty"`
+	RequestPath   *string            `json:"request_path,omitempty"`
+	CreatedAt     *int64             `json:"created_at,omitempty"`
+	Timestamp     *int64             `json:"timestamp,omitempty"`
+	ResourceData  json.RawMessage    `json:"resource_data,omitempty"`
+	OldObject     json.RawMessage    `json:"old_object,omitempty"`
+	NewObject     json.RawMessage    `json:"new_object,omitempty"`
+	Metadata      json.RawMessage    `json:"metadata,omitempty"`
 }
 
+// CreatedTime returns when the entry was recorded, or the zero time if unknown
+func (l *AuditLog) CreatedTime() time.Time {
+	if l.CreatedAt == nil || *l.CreatedAt <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(*l.CreatedAt)
+}
+
+// AuditResourceType is the type of resource an audit log entry refers to.
+// The API defines it as a free string; the constants below are the values
+// Gitness records, and other values are passed through unchanged.
+type AuditResourceType string
+
+// AuditResourceType constants
+const (
+	AuditResourceTypeRepository AuditResourceType = "repository"
+	AuditResourceTypeSpace      AuditResourceType = "space"
+	AuditResourceTypeSecret     AuditResourceType = "secret"
+	AuditResourceTypeConnector  AuditResourceType = "connector"
+	AuditResourceTypePipeline   AuditResourceType = "pipeline"
+	AuditResourceTypeUser       AuditResourceType = "user"
+)
+
+// AuditAction is the action recorded by an audit log entry. The API defines
+// it as a free string; the constants below are the values Gitness records.
+type AuditAction string
+
+// AuditAction constants
+const (
+	AuditActionCreated AuditAction = "created"
+	AuditActionUpdated AuditAction = "updated"
+	AuditActionDeleted AuditAction = "deleted"
+)
+
 // ListAuditLogsOptions specifies the optional parameters for listing audit logs
 type ListAuditLogsOptions struct {
 	ListOptions
-	UserUID            *string `url:"user_uid,omitempty"`
-	Action             *string `url:"action,omitempty"`
-	ResourceType       *string `url:"resource_type,omitempty"`
-	ResourceIdentifier *string `url:"resource_identifier,omitempty"`
-	From               *Time   `url:"from,omitempty"`
-	To                 *Time   `url:"to,omitempty"`
+	UserUID            *string            `url:"user_uid,omitempty"`
+	Action             *AuditAction       `url:"action,omitempty"`
+	ResourceType       *AuditResourceType `url:"resource_type,omitempty"`
+	ResourceIdentifier *string            `url:"resource_identifier,omitempty"`
+	SpacePath          *string            `url:"space_path,omitempty"`
+	// CreatedAfter and CreatedBefore bound the entries by creation time; they
+	// are sent as unix milliseconds
+	CreatedAfter  *time.Time `url:"created_after,omitempty"`
+	CreatedBefore *time.Time `url:"created_before,omitempty"`
 }
 
-// ListAuditLogs lists audit logs with optional filtering and pagination
-func (s *AuditService) ListAuditLogs(ctx context.Context, opt *ListAuditLogsOptions) ([]*AuditLog, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+// auditLogsResponse is the body returned by the audit log list endpoint
+type auditLogsResponse struct {
+	AuditLogs []*AuditLog `json:"audit_logs"`
+	Page      int         `json:"page"`
+	Size      int         `json:"size"`
+	Total     int         `json:"total"`
+}
 
-	// Add query parameters if options provided
-	if opt != nil {
-		// Add common query parameters
-		buildQueryParams(req, &opt.ListOptions)
+// ListAuditLogs lists audit logs with optional filtering and pagination. The
+// endpoint reports pagination in the response body rather than in headers;
+// it is copied into the Page, PerPage, Total, TotalPages and NextPage fields
+// of the returned Response.
+func (s *AuditService) ListAuditLogs(ctx context.Context, opt *ListAuditLogsOptions) ([]*AuditLog, *Response, error) {
+	if opt == nil {
+		opt = &ListAuditLogsOptions{}
+	}
 
-		// Add specific query parameters
+	var body auditLogsResponse
+	resp, err := s.client.performListRequestWithParams(ctx, "admin/audit", func(req httpRequest) {
+		s.client.buildQueryParams(req, &opt.ListOptions)
 		if opt.UserUID != nil {
 			req.SetQueryParam("user_uid", *opt.UserUID)
 		}
 		if opt.Action != nil {
-			req.SetQueryParam("action", *opt.Action)
+			req.SetQueryParam("action", string(*opt.Action))
 		}
 		if opt.ResourceType != nil {
-			req.SetQueryParam("resource_type", *opt.ResourceType)
+			req.SetQueryParam("resource_type", string(*opt.ResourceType))
 		}
 		if opt.ResourceIdentifier != nil {
 			req.SetQueryParam("resource_identifier", *opt.ResourceIdentifier)
 		}
-		if opt.From != nil {
-			req.SetQueryParam("from", opt.From.String())
+		if opt.SpacePath != nil {
+			req.SetQueryParam("space_path", *opt.SpacePath)
 		}
-		if opt.To != nil {
-			req.SetQueryParam("to", opt.To.String())
+		if opt.CreatedAfter != nil {
+			req.SetQueryParam("created_after", strconv.FormatInt(opt.CreatedAfter.UnixMilli(), 10))
 		}
+		if opt.CreatedBefore != nil {
+			req.SetQueryParam("created_before", strconv.FormatInt(opt.CreatedBefore.UnixMilli(), 10))
+		}
+	}, &body)
+	if err != nil {
+		return nil, resp, err
 	}
 
-	var logs []*AuditLog
-	req.SetSuccessResult(&logs)
+	setBodyPagination(resp, body.Page, body.Size, body.Total, len(body.AuditLogs))
+	return body.AuditLogs, resp, nil
+}
 
-	resp, err := req.Get("admin/audit")
-	if err != nil {
-		return nil, &Response{Response: resp}, err
+// setBodyPagination fills the pagination fields of resp from a response body
+// that reports the page number, the page size and the total item count.
+// count is the number of items on this page.
+func setBodyPagination(resp *Response, page, size, total, count int) {
+	if page <= 0 {
+		page = 1
 	}
-
-	if err := s.client.checkResponse(resp); err != nil {
-		return nil, &Response{Response: resp}, err
+	if size <= 0 {
+		size = count
+	}
+	resp.Page = Ptr(page)
+	resp.PerPage = Ptr(size)
+	resp.Total = Ptr(total)
+	if size > 0 {
+		resp.TotalPages = Ptr((total + size - 1) / size)
+	}
+	if count > 0 && (page-1)*size+count < total {
+		resp.NextPage = Ptr(page + 1)
 	}
-
-	// Parse pagination headers
-	response := &Response{Response: resp}
-	s.client.parsePaginationHeaders(response)
-
-	return logs, response, nil
 }
 
 // GetAuditLog retrieves a specific audit log entry by ID
@@ -105,6 +179,131 @@ func (s *AuditService) GetAuditLog(ctx context.Context, id int64) (*AuditLog, *R
 	return &log, resp, nil
 }
 
+// auditTailInterval is how often TailAuditLogs polls when no interval is given
+const auditTailInterval = 5 * time.Second
+
+// AuditTailState is the resumable position of an audit log tail. It
+// marshals to JSON so callers can persist it and resume after a restart
+// without re-emitting or skipping entries.
+type AuditTailState struct {
+	// LastID is the ID of the last entry delivered; 0 starts from the beginning
+	LastID int64 `json:"last_id"`
+	// LastCreatedAt is the created_at of the last entry delivered, in unix
+	// milliseconds as reported by the server
+	LastCreatedAt int64 `json:"last_created_at,omitempty"`
+}
+
+// advance records log as delivered
+func (st *AuditTailState) advance(log *AuditLog) {
+	st.LastID = *log.ID
+	if log.CreatedAt != nil && *log.CreatedAt > 0 {
+		st.LastCreatedAt = *log.CreatedAt
+	}
+}
+
+// createdAfter returns the created_after bound that still includes every
+// entry not yet delivered. It reaches one millisecond back because entries
+// can share the last entry's created_at; those already delivered are
+// skipped by ID.
+func (st *AuditTailState) createdAfter() *time.Time {
+	if st.LastCreatedAt <= 0 {
+		return nil
+	}
+	return Ptr(time.UnixMilli(st.LastCreatedAt - 1))
+}
+
+// TailAuditLogs polls for audit log entries matching opt and calls fn for
+// each new entry, oldest first, until ctx is done or fn returns an error.
+// Entries are tracked by ID, so every poll only fetches the pages newer than
+// the last seen entry. The first poll delivers all existing matching entries;
+// set opt.CreatedAfter to bound that backlog. A non-positive interval polls every
+// five seconds. It returns the context error once ctx is done.
+func (s *AuditService) TailAuditLogs(ctx context.Context, opt *ListAuditLogsOptions, interval time.Duration, fn func(*AuditLog) error) error {
+	return s.TailAuditLogsFrom(ctx, opt, &AuditTailState{}, interval, fn)
+}
+
+// TailAuditLogsFrom is like TailAuditLogs but resumes after the position in
+// state and advances it after fn returns nil for an entry. Persisting state
+// inside fn or after TailAuditLogsFrom returns therefore covers every entry
+// already handled, and resuming from it re-emits at most the entry being
+// handled when the process stopped. When opt.CreatedAfter is unset, every
+// poll, including the first one of a resumed tail, only asks for entries
+// created since state.LastCreatedAt.
+func (s *AuditService) TailAuditLogsFrom(ctx context.Context, opt *ListAuditLogsOptions, state *AuditTailState, interval time.Duration, fn func(*AuditLog) error) error {
+	if state == nil {
+		return fmt.Errorf("audit tail state must not be nil")
+	}
+	if interval <= 0 {
+		interval = auditTailInterval
+	}
+
+	var base ListAuditLogsOptions
+	if opt != nil {
+		base = *opt
+	}
+	base.Sort = Ptr("id")
+	base.Order = Ptr("desc")
+	narrow := base.CreatedAfter == nil
+
+	for {
+		if narrow {
+			base.CreatedAfter = state.createdAfter()
+		}
+		logs, err := s.auditLogsAfter(ctx, &base, state.LastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		for _, log := range logs {
+			if err := fn(log); err != nil {
+				return err
+			}
+			state.advance(log)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.client.clock.After(interval):
+		}
+	}
+}
+
+// auditLogsAfter returns the entries with an ID greater than lastID in
+// ascending order. opt must sort by descending ID so paging can stop at the
+// first entry already seen.
+func (s *AuditService) auditLogsAfter(ctx context.Context, opt *ListAuditLogsOptions, lastID int64) ([]*AuditLog, error) {
+	var newer []*AuditLog
+	page := 1
+	for {
+		o := *opt
+		o.Page = Ptr(page)
+		logs, resp, err := s.ListAuditLogs(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, log := range logs {
+			if log.ID == nil {
+				continue
+			}
+			if *log.ID <= lastID {
+				slices.Reverse(newer)
+				return newer, nil
+			}
+			newer = append(newer, log)
+		}
+
+		if resp.NextPage == nil || *resp.NextPage <= page || len(logs) == 0 {
+			slices.Reverse(newer)
+			return newer, nil
+		}
+		page = *resp.NextPage
+	}
+}
+
 // CleanupAuditLogs initiates cleanup of audit logs
 func (s *AuditService) CleanupAuditLogs(ctx context.Context) (*Response, error) {
 	resp, err := s.client.Post(ctx, "admin/audit/cleanup", nil, nil)
@@ -131,7 +330,7 @@ type ListUsersOptions struct {
 
 // ListUsers lists users with optional filtering
 func (s *AdminService) ListUsers(ctx context.Context, opt *ListUsersOptions) ([]*User, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx)
 
 	// Add query parameters if options provided
 	if opt != nil {
@@ -161,7 +360,7 @@ func (s *AdminService) ListUsers(ctx context.Context, opt *ListUsersOptions) ([]
 	var users []*User
 	req.SetSuccessResult(&users)
 
-	resp, err := req.Get("admin/users")
+	resp, err := req.Get(s.client.buildFullURL("admin/users"))
 	if err != nil {
 		return nil, &Response{Response: resp}, err
 	}
@@ -177,6 +376,19 @@ func (s *AdminService) ListUsers(ctx context.Context, opt *ListUsersOptions) ([]
 	return users, response, nil
 }
 
+// ListUsersPaginated lists users and returns a Page that can fetch the next page
+func (s *AdminService) ListUsersPaginated(ctx context.Context, opt *ListUsersOptions) (*Page[User], error) {
+	var base ListUsersOptions
+	if opt != nil {
+		base = *opt
+	}
+	return fetchPage(ctx, base.Page, func(ctx context.Context, page int) ([]*User, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListUsers(ctx, &o)
+	})
+}
+
 // GetUser retrieves a specific user by UID
 func (s *AdminService) GetUser(ctx context.Context, userUID string) (*User, *Response, error) {
 	path := fmt.Sprintf("admin/users/%s", url.PathEscape(userUID))
@@ -272,19 +484,21 @@ type SearchLDAPUsersOptions struct {
 
 // SearchLDAPUsers searches for LDAP users
 func (s *AdminService) SearchLDAPUsers(ctx context.Context, opt *SearchLDAPUsersOptions) ([]*LDAPUser, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+	if opt == nil {
+		opt = &SearchLDAPUsersOptions{}
+	}
 
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
-		if opt.Query != nil {
-			req.SetQueryParam("query", *opt.Query)
-		}
+	req := s.client.newRequest(ctx)
+
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.Query != nil {
+		req.SetQueryParam("query", *opt.Query)
 	}
 
 	var users []*LDAPUser
 	req.SetSuccessResult(&users)
 
-	resp, err := req.Get("admin/ldap/users")
+	resp, err := req.Get(s.client.buildFullURL("admin/ldap/users"))
 	if err != nil {
 		return nil, &Response{Response: resp}, err
 	}
@@ -0,0 +1,228 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestTailAuditLogs tests that each poll delivers only entries newer than the last seen ID
+func TestTailAuditLogs(t *testing.T) {
+	polls := [][][]int64{
+		{{3, 2}, {1}},
+		{{4, 3}},
+		{{5, 4}},
+	}
+	poll, requests := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("sort") != "id" || r.URL.Query().Get("order") != "desc" || r.URL.Query().Get("action") != "created" {
+			t.Errorf("Unexpected query %q", r.URL.RawQuery)
+		}
+		pages := polls[poll]
+		page := 0
+		if r.URL.Query().Get("page") == "2" {
+			page = 1
+		}
+		if page+1 == len(pages) {
+			poll++
+		}
+
+		var logs []*AuditLog
+		total := 0
+		for i, p := range pages {
+			total += len(p)
+			if i == page {
+				for _, id := range p {
+					logs = append(logs, &AuditLog{ID: Ptr(id)})
+				}
+			}
+		}
+		writeAuditLogs(w, page+1, len(pages[0]), total, logs)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithClock(clock))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var seen []int64
+	err = client.Audit.TailAuditLogs(ctx, &ListAuditLogsOptions{Action: Ptr(AuditActionCreated)}, time.Minute, func(log *AuditLog) error {
+		seen = append(seen, *log.ID)
+		if *log.ID == 5 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if want := []int64{1, 2, 3, 4, 5}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("Expected entries %v, got %v", want, seen)
+	}
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+	if clock.waits[0] != time.Minute {
+		t.Errorf("Expected a one minute interval, got %v", clock.waits[0])
+	}
+}
+
+// TestTailAuditLogsFrom tests resuming from a persisted state
+func TestTailAuditLogsFrom(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	var afters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		afters = append(afters, r.URL.Query().Get("created_after"))
+		writeAuditLogs(w, 1, 30, 4, []*AuditLog{
+			{ID: Ptr(int64(6)), CreatedAt: Ptr(created + 120000)},
+			{ID: Ptr(int64(5)), CreatedAt: Ptr(created + 60000)},
+			{ID: Ptr(int64(4)), CreatedAt: Ptr(created)},
+			{ID: Ptr(int64(3)), CreatedAt: Ptr(created)},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithClock(&fakeClock{}))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	var state AuditTailState
+	if err := json.Unmarshal([]byte(`{"last_id": 3, "last_created_at": 1740830400000}`), &state); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+
+	stop := errors.New("stop")
+	var seen []int64
+	err = client.Audit.TailAuditLogsFrom(context.Background(), nil, &state, time.Second, func(log *AuditLog) error {
+		if *log.ID == 6 {
+			return stop
+		}
+		seen = append(seen, *log.ID)
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	// Entry 4 shares the last delivered created_at and must not be skipped
+	if want := []int64{4, 5}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("Expected entries %v before stopping, got %v", want, seen)
+	}
+	if want := []string{strconv.FormatInt(created-1, 10)}; !reflect.DeepEqual(afters, want) {
+		t.Errorf("Expected the query to start just before the last created_at, got %v", afters)
+	}
+
+	data, err := json.Marshal(&state)
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	if want := `{"last_id":5,"last_created_at":1740830460000}`; string(data) != want {
+		t.Errorf("Expected state %s, got %s", want, data)
+	}
+}
+
+// TestListAuditLogsTypedFilters tests that typed filters are sent and the
+// response body, including its pagination, is decoded
+func TestListAuditLogsTypedFilters(t *testing.T) {
+	after := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/audit", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("resource_type") != "repository" || q.Get("action") != "deleted" ||
+			q.Get("created_after") != "1740787200000" || q.Get("page") != "2" || q.Get("limit") != "1" {
+			t.Errorf("Unexpected query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"audit_logs": [{"id": 1, "action": "deleted", "resource_type": "repository",
+			"resource_id": "space/repo", "user_uid": "admin", "created_at": 1740787260000}],
+			"page": 2, "size": 1, "total": 3}`))
+	})
+
+	client := NewTestClient(mux)
+
+	logs, resp, err := client.Audit.ListAuditLogs(context.Background(), &ListAuditLogsOptions{
+		ListOptions:  ListOptions{Page: Ptr(2), Limit: Ptr(1)},
+		ResourceType: Ptr(AuditResourceTypeRepository),
+		Action:       Ptr(AuditActionDeleted),
+		CreatedAfter: Ptr(after),
+	})
+	if err != nil {
+		t.Fatalf("ListAuditLogs returned error: %v", err)
+	}
+	if len(logs) != 1 || *logs[0].ResourceType != "repository" || *logs[0].Action != "deleted" ||
+		*logs[0].ResourceID != "space/repo" || *logs[0].UserUID != "admin" || !logs[0].CreatedTime().Equal(after.Add(time.Minute)) {
+		t.Errorf("Unexpected logs %+v", logs)
+	}
+	if *resp.Page != 2 || *resp.Total != 3 || *resp.TotalPages != 3 || resp.NextPage == nil || *resp.NextPage != 3 {
+		t.Errorf("Unexpected pagination page=%v total=%v pages=%v next=%v", resp.Page, resp.Total, resp.TotalPages, resp.NextPage)
+	}
+}
+
+// TestAuditFilterConstants tests the query encoding of every audit resource
+// type and action constant
+func TestAuditFilterConstants(t *testing.T) {
+	resourceTypes := map[AuditResourceType]string{
+		AuditResourceTypeRepository: "repository",
+		AuditResourceTypeSpace:      "space",
+		AuditResourceTypeSecret:     "secret",
+		AuditResourceTypeConnector:  "connector",
+		AuditResourceTypePipeline:   "pipeline",
+		AuditResourceTypeUser:       "user",
+	}
+	actions := map[AuditAction]string{
+		AuditActionCreated: "created",
+		AuditActionUpdated: "updated",
+		AuditActionDeleted: "deleted",
+	}
+
+	var gotResourceType, gotAction string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/audit", func(w http.ResponseWriter, r *http.Request) {
+		gotResourceType = r.URL.Query().Get("resource_type")
+		gotAction = r.URL.Query().Get("action")
+		writeAuditLogs(w, 1, 30, 0, nil)
+	})
+
+	client := NewTestClient(mux)
+
+	for resourceType, want := range resourceTypes {
+		if _, _, err := client.Audit.ListAuditLogs(context.Background(), &ListAuditLogsOptions{ResourceType: Ptr(resourceType)}); err != nil {
+			t.Fatalf("ListAuditLogs returned error: %v", err)
+		}
+		if gotResourceType != want {
+			t.Errorf("Expected resource_type %q, got %q", want, gotResourceType)
+		}
+	}
+	for action, want := range actions {
+		if _, _, err := client.Audit.ListAuditLogs(context.Background(), &ListAuditLogsOptions{Action: Ptr(action)}); err != nil {
+			t.Fatalf("ListAuditLogs returned error: %v", err)
+		}
+		if gotAction != want {
+			t.Errorf("Expected action %q, got %q", want, gotAction)
+		}
+	}
+}
+
+// writeAuditLogs writes logs in the body shape of the audit log list endpoint
+func writeAuditLogs(w http.ResponseWriter, page, size, total int, logs []*AuditLog) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"audit_logs": logs, "page": page, "size": size, "total": total})
+}
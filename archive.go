@@ -0,0 +1,160 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ArchiveFormat is the file format of a repository archive
+type ArchiveFormat string
+
+// ArchiveFormat constants
+const (
+	ArchiveFormatTar   ArchiveFormat = "tar"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+	ArchiveFormatTgz   ArchiveFormat = "tgz"
+	ArchiveFormatZip   ArchiveFormat = "zip"
+)
+
+// defaultDownloadResumes is how often DownloadArchiveTo resumes an interrupted download by default
+const defaultDownloadResumes = 3
+
+// DownloadOptions specifies options for downloading a repository archive
+type DownloadOptions struct {
+	// Format of the archive; defaults to tar.gz
+	Format ArchiveFormat
+	// Prefix is prepended as a directory to every path in the archive
+	Prefix *string
+	// Paths limits the archive to the given files and directories
+	Paths []string
+
+	// Resume continues an interrupted download with a Range request from the
+	// last byte received instead of failing
+	Resume bool
+	// MaxResumes bounds the number of resumes; 0 uses the default of three
+	MaxResumes int
+}
+
+// DownloadArchiveTo downloads an archive of the repository at ref and writes
+// it to w, returning the number of bytes written.
+//
+// With opt.Resume set, a download interrupted by a connection error is
+// continued from the last byte received, provided the response carried a
+// strong ETag; without one the error is returned, as a ref such as a branch
+// may have moved. The resumed request sends the ETag in If-Range, so if the
+// server no longer serves identical content, or does not support ranges, it
+// answers with the full archive and the download restarts at offset 0. This
+// is why w must be an io.WriterAt.
+//
+// The returned count is the final archive size. After a restart, w is
+// truncated to it when w has a Truncate(int64) error method, as *os.File
+// does; other writers may hold stale bytes past the count.
+func (s *RepositoriesService) DownloadArchiveTo(ctx context.Context, repoPath, ref string, w io.WriterAt, opt *DownloadOptions) (int64, *Response, error) {
+	if opt == nil {
+		opt = &DownloadOptions{}
+	}
+	format := opt.Format
+	if format == "" {
+		format = ArchiveFormatTarGz
+	}
+	maxResumes := opt.MaxResumes
+	if maxResumes <= 0 {
+		maxResumes = defaultDownloadResumes
+	}
+
+	path := fmt.Sprintf("repos/%s/archive/%s.%s", url.PathEscape(repoPath), url.PathEscape(ref), format)
+	var offset int64
+	var etag string
+	var restarted bool
+	for resumes := 0; ; resumes++ {
+		req := s.client.newRequest(ctx).DisableAutoReadResponse()
+		if opt.Prefix != nil {
+			req.SetQueryParam("prefix", *opt.Prefix)
+		}
+		if len(opt.Paths) > 0 {
+			req.AddQueryParams("path", opt.Paths...)
+		}
+		if offset > 0 {
+			req.SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+			if etag != "" {
+				req.SetHeader("If-Range", etag)
+			}
+		}
+
+		resp, err := req.Get(s.client.buildFullURL(path))
+		response := &Response{Response: resp}
+		if err != nil {
+			if opt.Resume && resumes < maxResumes && ctx.Err() == nil && IsConnectionError(err) {
+				continue
+			}
+			return offset, response, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			// A full response restarts the download
+			restarted = restarted || offset > 0
+			offset = 0
+			etag = resp.Header.Get("ETag")
+		case http.StatusPartialContent:
+			if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+				resp.Body.Close()
+				return offset, response, fmt.Errorf("unexpected Content-Range %q resuming at byte %d", resp.Header.Get("Content-Range"), offset)
+			}
+		default:
+			_, readErr := resp.ToBytes()
+			resp.Body.Close()
+			if readErr != nil {
+				return offset, response, readErr
+			}
+			return offset, response, s.client.checkResponse(resp)
+		}
+
+		body := newContextBody(ctx, resp.Body)
+		n, err := io.Copy(io.NewOffsetWriter(w, offset), body)
+		body.Close()
+		offset += n
+		if err == nil {
+			if t, ok := w.(interface{ Truncate(int64) error }); ok && restarted {
+				if err := t.Truncate(offset); err != nil {
+					return offset, response, err
+				}
+			}
+			return offset, response, nil
+		}
+		if !opt.Resume || resumes >= maxResumes || ctx.Err() != nil || !IsConnectionError(err) || !isStrongETag(etag) {
+			return offset, response, err
+		}
+	}
+}
+
+// isStrongETag reports whether etag is a strong validator, as If-Range requires
+func isStrongETag(etag string) bool {
+	return strings.HasPrefix(etag, `"`)
+}
+
+// contentRangeStart returns the first byte position of a Content-Range header
+// such as "bytes 100-199/200"
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestDownloadArchiveToResume tests resuming an interrupted download with a Range request
+func TestDownloadArchiveToResume(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.EscapedPath(), "/repos/space%2Frepo/archive/main.zip") {
+			t.Errorf("Unexpected path %q", r.URL.EscapedPath())
+		}
+		ranges = append(ranges, r.Header.Get("Range")+"|"+r.Header.Get("If-Range"))
+
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(content[start:])
+			return
+		}
+
+		// Send the first 400 bytes, then drop the connection
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(content[:400])
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatalf("Hijack failed: %v", err)
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "archive.zip"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	n, _, err := client.Repositories.DownloadArchiveTo(ctx, "space/repo", "main", file, &DownloadOptions{Format: ArchiveFormatZip, Resume: true})
+	if err != nil {
+		t.Fatalf("DownloadArchiveTo returned error: %v", err)
+	}
+	if n != int64(len(content)) {
+		t.Errorf("Expected %d bytes, got %d", len(content), n)
+	}
+	got, _ := os.ReadFile(file.Name())
+	if !bytes.Equal(got, content) {
+		t.Error("Downloaded content does not match")
+	}
+	if want := []string{"|", `bytes=400-|"v1"`}; len(ranges) != 2 || ranges[0] != want[0] || ranges[1] != want[1] {
+		t.Errorf("Expected requests %q, got %q", want, ranges)
+	}
+
+	ranges = nil
+	if _, _, err := client.Repositories.DownloadArchiveTo(ctx, "space/repo", "main", file, &DownloadOptions{Format: ArchiveFormatZip}); err == nil {
+		t.Error("Expected error without resume")
+	}
+	if len(ranges) != 1 {
+		t.Errorf("Expected a single request without resume, got %d", len(ranges))
+	}
+}
+
+// TestDownloadArchiveToRequiresStrongETag tests that a download without a strong ETag is not resumed
+func TestDownloadArchiveToRequiresStrongETag(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `W/"v1"`)
+		w.Header().Set("Content-Length", "1000")
+		w.WriteHeader(http.StatusOK)
+		w.Write(make([]byte, 400))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatalf("Hijack failed: %v", err)
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "archive.zip"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer file.Close()
+
+	if _, _, err := client.Repositories.DownloadArchiveTo(context.Background(), "space/repo", "main", file, &DownloadOptions{Resume: true}); err == nil {
+		t.Error("Expected error for a weak ETag")
+	}
+	if requests != 1 {
+		t.Errorf("Expected no resume with a weak ETag, got %d requests", requests)
+	}
+}
+
+// TestDownloadArchiveToRestartTruncates tests that a full response to a resume restarts the download and truncates the file
+func TestDownloadArchiveToRestartTruncates(t *testing.T) {
+	content := bytes.Repeat([]byte("abcde"), 100)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			// The archive changed, so If-Range fails and the full archive is sent
+			w.Header().Set("ETag", `"v2"`)
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(content)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", "2000")
+		w.WriteHeader(http.StatusOK)
+		w.Write(bytes.Repeat([]byte("x"), 800))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatalf("Hijack failed: %v", err)
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "archive.zip"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	defer file.Close()
+
+	n, _, err := client.Repositories.DownloadArchiveTo(context.Background(), "space/repo", "main", file, &DownloadOptions{Resume: true})
+	if err != nil {
+		t.Fatalf("DownloadArchiveTo returned error: %v", err)
+	}
+	if n != int64(len(content)) {
+		t.Errorf("Expected %d bytes, got %d", len(content), n)
+	}
+	got, _ := os.ReadFile(file.Name())
+	if !bytes.Equal(got, content) {
+		t.Errorf("Expected the file to hold only the restarted archive, got %d bytes", len(got))
+	}
+}
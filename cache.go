@@ -0,0 +1,173 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/imroc/req/v3"
+)
+
+// cacheHitHeader marks responses that were served from a Cache after a 304
+const cacheHitHeader = "X-From-Cache"
+
+// maxCachedBodySize is the largest response body WithResponseCache stores
+const maxCachedBodySize = 1 << 20
+
+// CachedResponse is a GET response stored together with its ETag
+type CachedResponse struct {
+	ETag       string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Cache stores ETag-validated GET responses keyed by request URL and the
+// credentials the request was sent with. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+}
+
+// MemoryCache is an unbounded in-memory Cache
+type MemoryCache struct {
+	entries sync.Map
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{}
+}
+
+// Get returns the cached response for key
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	v, ok := m.entries.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*CachedResponse), true
+}
+
+// Set stores resp under key
+func (m *MemoryCache) Set(key string, resp *CachedResponse) {
+	m.entries.Store(key, resp)
+}
+
+// WithResponseCache enables ETag caching for GET requests. Responses carrying
+// an ETag and a Content-Length of at most 1 MiB are stored in cache; later
+// requests for the same URL and token send If-None-Match and a 304 reply is
+// answered with the stored body. Streamed downloads are never cached.
+// Response.FromCache reports whether a response was served this way.
+func WithResponseCache(cache Cache) ClientOptionFunc {
+	return func(c *Client) error {
+		if cache == nil {
+			return nil
+		}
+		c.client.GetTransport().WrapRoundTripFunc(func(rt http.RoundTripper) req.HttpRoundTripFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				if r.Method != http.MethodGet || skipResponseCache(r.Context()) {
+					return rt.RoundTrip(r)
+				}
+
+				key := cacheKey(r)
+				cached, ok := cache.Get(key)
+				if ok && r.Header.Get("If-None-Match") == "" {
+					r = r.Clone(r.Context())
+					r.Header.Set("If-None-Match", cached.ETag)
+				}
+
+				resp, err := rt.RoundTrip(r)
+				if err != nil {
+					return resp, err
+				}
+
+				if resp.StatusCode == http.StatusNotModified && ok {
+					resp.Body.Close()
+					return cached.response(r), nil
+				}
+
+				etag := resp.Header.Get("ETag")
+				if resp.StatusCode != http.StatusOK || etag == "" ||
+					resp.ContentLength < 0 || resp.ContentLength > maxCachedBodySize {
+					return resp, nil
+				}
+
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+				cache.Set(key, &CachedResponse{
+					ETag:       etag,
+					StatusCode: resp.StatusCode,
+					Header:     resp.Header.Clone(),
+					Body:       body,
+				})
+				return resp, nil
+			}
+		})
+		return nil
+	}
+}
+
+// cacheKey identifies r by URL and a hash of its Authorization header, so
+// clients sharing a Cache never see responses fetched with another token
+func cacheKey(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		return r.URL.String()
+	}
+	sum := sha256.Sum256([]byte(auth))
+	return hex.EncodeToString(sum[:8]) + " " + r.URL.String()
+}
+
+type skipResponseCacheKey struct{}
+
+// withoutResponseCache marks requests made with ctx as streamed, so the
+// response cache neither buffers nor stores their bodies
+func withoutResponseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipResponseCacheKey{}, true)
+}
+
+// skipResponseCache reports whether ctx was marked with withoutResponseCache
+func skipResponseCache(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipResponseCacheKey{}).(bool)
+	return skip
+}
+
+// response rebuilds an *http.Response for r from the cached entry
+func (cr *CachedResponse) response(r *http.Request) *http.Response {
+	header := cr.Header.Clone()
+	header.Set(cacheHitHeader, "1")
+	header.Set("Content-Length", strconv.Itoa(len(cr.Body)))
+	return &http.Response{
+		Status:        strconv.Itoa(cr.StatusCode) + " " + http.StatusText(cr.StatusCode),
+		StatusCode:    cr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cr.Body)),
+		ContentLength: int64(len(cr.Body)),
+		Request:       r,
+	}
+}
+
+// FromCache reports whether the response body was served from the client's
+// Cache after the server answered 304 Not Modified
+func (r *Response) FromCache() bool {
+	return r.Header(cacheHitHeader) != ""
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestResponseCacheServesNotModified tests that a 304 reply is answered from the cache
+func TestResponseCacheServesNotModified(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if requests > 1 {
+			t.Errorf("Expected If-None-Match on request %d", requests)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"gitspace_enabled":true,"ui":{"show_plugin":true}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithResponseCache(NewMemoryCache()))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		config, resp, err := client.System.GetSystemConfig(ctx)
+		if err != nil {
+			t.Fatalf("GetSystemConfig returned error: %v", err)
+		}
+		if config.GitspaceEnabled == nil || !*config.GitspaceEnabled {
+			t.Errorf("Request %d: expected gitspace_enabled true, got %v", i, config.GitspaceEnabled)
+		}
+		if want := i > 0; resp.FromCache() != want {
+			t.Errorf("Request %d: expected FromCache %v, got %v", i, want, resp.FromCache())
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+// TestResponseCacheSkipsWrites tests that non-GET requests are never cached
+func TestResponseCacheSkipsWrites(t *testing.T) {
+	cache := NewMemoryCache()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("Unexpected If-None-Match on %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithResponseCache(cache))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Post(ctx, "test", map[string]string{}, nil); err != nil {
+			t.Fatalf("Post returned error: %v", err)
+		}
+	}
+	if n := cacheEntries(cache); n != 0 {
+		t.Errorf("Expected POST response not to be cached, got %d entries", n)
+	}
+}
+
+// TestResponseCacheSkipsUnboundedBodies tests that chunked and streamed responses are never cached
+func TestResponseCacheSkipsUnboundedBodies(t *testing.T) {
+	cache := NewMemoryCache()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("Unexpected If-None-Match on %s", r.URL.Path)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{}`))
+		if r.URL.Path == "/api/v1/chunked" {
+			// Flushing before the handler returns drops the Content-Length
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithResponseCache(cache))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.Get(ctx, "chunked", nil); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if n := cacheEntries(cache); n != 0 {
+		t.Errorf("Expected chunked response not to be cached, got %d entries", n)
+	}
+
+	resp, err := client.newRequest(ctx).DisableAutoReadResponse().Get(client.buildFullURL("stream"))
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	resp.Body.Close()
+	if n := cacheEntries(cache); n != 0 {
+		t.Errorf("Expected streamed response not to be cached, got %d entries", n)
+	}
+}
+
+// TestResponseCacheKeyedByToken tests that clients sharing a cache do not see each other's responses
+func TestResponseCacheKeyedByToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if r.Header.Get("If-None-Match") == `"`+token+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"`+token+`"`)
+		w.Write([]byte(`{"uid":"` + token + `"}`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	ctx := context.Background()
+	for _, token := range []string{"token-a", "token-b", "token-a"} {
+		client, err := NewClient(token, WithBaseURL(server.URL+"/"), WithResponseCache(cache))
+		if err != nil {
+			t.Fatalf("NewClient returned error: %v", err)
+		}
+		user, _, err := client.Users.GetCurrentUser(ctx)
+		if err != nil {
+			t.Fatalf("GetCurrentUser returned error: %v", err)
+		}
+		if want := "Bearer " + token; user.UID == nil || *user.UID != want {
+			t.Errorf("Expected user %q, got %v", want, user.UID)
+		}
+	}
+	if n := cacheEntries(cache); n != 2 {
+		t.Errorf("Expected 2 cache entries, got %d", n)
+	}
+}
+
+// cacheEntries counts the responses stored in cache
+func cacheEntries(cache *MemoryCache) int {
+	n := 0
+	cache.entries.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
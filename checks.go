@@ -8,10 +8,19 @@ package gitness
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 )
 
+// createChecksConcurrency bounds the number of in-flight requests in CreateChecks
+const createChecksConcurrency = 8
+
 // ChecksService handles communication with check related methods
 type ChecksService struct {
 	client *Client
@@ -60,17 +69,59 @@ type ListChecksOptions struct {
 	Latest *bool `url:"latest,omitempty"`
 }
 
-// CreateCheck creates a check for a commit
+// CreateCheck reports a check for a commit. The server upserts by identifier,
+// so reporting the same identifier again replaces the earlier result.
 func (s *ChecksService) CreateCheck(ctx context.Context, repoPath, commitSHA string, opt *CreateCheckOptions) (*Check, *Response, error) {
-	path := fmt.Sprintf("repos/%s/commits/%s/checks", url.PathEscape(repoPath), commitSHA)
+	path := fmt.Sprintf("repos/%s/checks/commits/%s", url.PathEscape(repoPath), url.PathEscape(commitSHA))
 	var check Check
-	resp, err := s.client.Post(ctx, path, opt, &check)
+	resp, err := s.client.Put(ctx, path, opt, &check)
 	if err != nil {
 		return nil, resp, err
 	}
 	return &check, resp, nil
 }
 
+// CreateCheckResult holds the outcome of a single check submitted by CreateChecks
+type CreateCheckResult struct {
+	Check    *Check
+	Response *Response
+	Err      error
+}
+
+// CreateChecks creates several checks for a commit concurrently. Results are
+// returned in the same order as checks; the returned error joins all
+// per-check failures and is nil when every check was created.
+func (s *ChecksService) CreateChecks(ctx context.Context, repoPath, commitSHA string, checks []*CreateCheckOptions) ([]*CreateCheckResult, error) {
+	results := make([]*CreateCheckResult, len(checks))
+	sem := make(chan struct{}, createChecksConcurrency)
+
+	var wg sync.WaitGroup
+	for i, opt := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			check, resp, err := s.CreateCheck(ctx, repoPath, commitSHA, opt)
+			results[i] = &CreateCheckResult{Check: check, Response: resp, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			identifier := ""
+			if checks[i] != nil && checks[i].Identifier != nil {
+				identifier = *checks[i].Identifier
+			}
+			errs = append(errs, fmt.Errorf("check %q: %w", identifier, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
 // UpdateCheck updates a check
 func (s *ChecksService) UpdateCheck(ctx context.Context, repoPath, commitSHA, checkIdentifier string, opt *UpdateCheckOptions) (*Check, *Response, error) {
 	path := fmt.Sprintf("repos/%s/commits/%s/checks/%s", url.PathEscape(repoPath), url.PathEscape(commitSHA), url.PathEscape(checkIdentifier))
@@ -84,8 +135,8 @@ func (s *ChecksService) UpdateCheck(ctx context.Context, repoPath, commitSHA, ch
 
 // ListChecks lists checks for a commit
 func (s *ChecksService) ListChecks(ctx context.Context, repoPath, commitSHA string, opt *ListChecksOptions) ([]*Check, *Response, error) {
-	path := fmt.Sprintf("repos/%s/commits/%s/checks", url.PathEscape(repoPath), commitSHA)
-	req := s.client.client.R().SetContext(ctx)
+	path := fmt.Sprintf("repos/%s/checks/commits/%s", url.PathEscape(repoPath), url.PathEscape(commitSHA))
+	req := s.client.newRequest(ctx)
 
 	// Add specific query parameters
 	if opt != nil && opt.Latest != nil {
@@ -130,31 +181,100 @@ type TemplatesService struct {
 
 // Template represents a Gitness template
 type Template struct {
-	Identifier  *string `json:"identifier,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Data        *string `json:"data,omitempty"`
-	Type        *string `json:"type,omitempty"`
-	SpaceID     *int64  `json:"space_id,omitempty"`
-	Created     *Time   `json:"created,omitempty"`
-	Updated     *Time   `json:"updated,omitempty"`
+	Identifier  *string       `json:"identifier,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Data        *string       `json:"data,omitempty"`
+	Type        *TemplateType `json:"type,omitempty"`
+	SpaceID     *int64        `json:"space_id,omitempty"`
+	Created     *Time         `json:"created,omitempty"`
+	Updated     *Time         `json:"updated,omitempty"`
 }
 
+// TemplateType represents the kind of pipeline element a template defines
+type TemplateType string
+
+// TemplateType constants
+const (
+	TemplateTypeStage TemplateType = "stage"
+	TemplateTypeStep  TemplateType = "step"
+)
+
 // CreateTemplateOptions specifies options for creating a template
 type CreateTemplateOptions struct {
-	Identifier  *string `json:"identifier,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Data        *string `json:"data,omitempty"`
-	Type        *string `json:"type,omitempty"`
+	Identifier  *string       `json:"identifier,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Data        *string       `json:"data,omitempty"`
+	Type        *TemplateType `json:"type,omitempty"`
+
+	// Validate checks Data with the ValidateTemplateData heuristic before
+	// sending the request. Off by default.
+	Validate *bool `json:"-"`
 }
 
 // UpdateTemplateOptions specifies options for updating a template
 type UpdateTemplateOptions struct {
 	Description *string `json:"description,omitempty"`
 	Data        *string `json:"data,omitempty"`
+
+	// Validate checks Data with the ValidateTemplateData heuristic before
+	// sending the request. Off by default.
+	Validate *bool `json:"-"`
+}
+
+// TemplateDataError describes malformed template YAML
+type TemplateDataError struct {
+	Line    int
+	Message string
+}
+
+func (e *TemplateDataError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("invalid template data: line %d: %s", e.Line, e.Message)
+	}
+	return "invalid template data: " + e.Message
+}
+
+// ValidateTemplateData performs a lightweight check of template YAML without
+// a YAML dependency: the document must be non-empty, no line may be indented
+// with a leading tab, and the first entry must be a mapping rather than a
+// sequence or bare scalar. It is a heuristic, not a parser, so it only
+// catches common mistakes and some invalid documents pass; the server
+// remains the authority. CreateTemplate and UpdateTemplate run it only when
+// Validate is set.
+func ValidateTemplateData(data string) error {
+	if strings.TrimSpace(data) == "" {
+		return &TemplateDataError{Message: "data is empty"}
+	}
+
+	sawEntry := false
+	for i, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			return &TemplateDataError{Line: i + 1, Message: "tabs are not allowed for indentation"}
+		}
+		trimmed := strings.TrimSpace(line)
+		if sawEntry || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "---") {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "{") && (strings.HasPrefix(trimmed, "-") || !strings.Contains(trimmed, ":")) {
+			return &TemplateDataError{Line: i + 1, Message: "expected a mapping"}
+		}
+		sawEntry = true
+	}
+	return nil
 }
 
 // CreateTemplate creates a new template
 func (s *TemplatesService) CreateTemplate(ctx context.Context, spaceRef string, opt *CreateTemplateOptions) (*Template, *Response, error) {
+	if opt != nil && opt.Validate != nil && *opt.Validate {
+		var data string
+		if opt.Data != nil {
+			data = *opt.Data
+		}
+		if err := ValidateTemplateData(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	path := fmt.Sprintf("spaces/%s/templates", url.PathEscape(spaceRef))
 	var template Template
 	resp, err := s.client.Post(ctx, path, opt, &template)
@@ -177,7 +297,7 @@ func (s *TemplatesService) ListTemplates(ctx context.Context, spaceRef string, o
 
 // GetTemplate retrieves a specific template
 func (s *TemplatesService) GetTemplate(ctx context.Context, spaceRef, templateIdentifier string) (*Template, *Response, error) {
-	path := fmt.Sprintf("spaces/%s/templates/%s", url.PathEscape(spaceRef), templateIdentifier)
+	path := fmt.Sprintf("spaces/%s/templates/%s", url.PathEscape(spaceRef), url.PathEscape(templateIdentifier))
 	var template Template
 	resp, err := s.client.Get(ctx, path, &template)
 	if err != nil {
@@ -188,7 +308,13 @@ func (s *TemplatesService) GetTemplate(ctx context.Context, spaceRef, templateId
 
 // UpdateTemplate updates a template
 func (s *TemplatesService) UpdateTemplate(ctx context.Context, spaceRef, templateIdentifier string, opt *UpdateTemplateOptions) (*Template, *Response, error) {
-	path := fmt.Sprintf("spaces/%s/templates/%s", url.PathEscape(spaceRef), templateIdentifier)
+	if opt != nil && opt.Data != nil && opt.Validate != nil && *opt.Validate {
+		if err := ValidateTemplateData(*opt.Data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	path := fmt.Sprintf("spaces/%s/templates/%s", url.PathEscape(spaceRef), url.PathEscape(templateIdentifier))
 	var template Template
 	resp, err := s.client.Patch(ctx, path, opt, &template)
 	if err != nil {
@@ -199,7 +325,126 @@ func (s *TemplatesService) UpdateTemplate(ctx context.Context, spaceRef, templat
 
 // DeleteTemplate deletes a template
 func (s *TemplatesService) DeleteTemplate(ctx context.Context, spaceRef, templateIdentifier string) (*Response, error) {
-	path := fmt.Sprintf("spaces/%s/templates/%s", url.PathEscape(spaceRef), templateIdentifier)
+	path := fmt.Sprintf("spaces/%s/templates/%s", url.PathEscape(spaceRef), url.PathEscape(templateIdentifier))
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
+
+// CopyTemplateAndDelete recreates a template, including its type and data,
+// under targetSpaceRef with the same identifier, then deletes the original.
+// templateRef is of the form space/identifier. Only pipelines in the target
+// space or below it can still use the template. Gitness has no move endpoint,
+// so a failed delete leaves both copies; the new one is returned with the error.
+func (s *TemplatesService) CopyTemplateAndDelete(ctx context.Context, templateRef, targetSpaceRef string) (*Template, *Response, error) {
+	spaceRef, identifier, err := splitSpaceRef("template", templateRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, resp, err := s.GetTemplate(ctx, spaceRef, identifier)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	copied, resp, err := s.CreateTemplate(ctx, targetSpaceRef, &CreateTemplateOptions{
+		Identifier:  template.Identifier,
+		Description: template.Description,
+		Data:        template.Data,
+		Type:        template.Type,
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp, err := s.DeleteTemplate(ctx, spaceRef, identifier); err != nil {
+		return copied, resp, fmt.Errorf("delete original template %q: %w", templateRef, err)
+	}
+	return copied, resp, nil
+}
+
+// templateInputReference matches input expressions such as ${{ inputs.image }}
+var templateInputReference = regexp.MustCompile(`\$\{\{\s*inputs\.([A-Za-z_][\w-]*)\s*\}\}`)
+
+// ResolveTemplate returns the template's YAML with every ${{ inputs.<name> }}
+// expression replaced by the matching value from inputs. Gitness does not
+// expose an expansion endpoint, so the template is fetched and expanded
+// client-side. An expression that makes up a whole value is replaced by the
+// input encoded as a JSON scalar or flow collection, which YAML reads
+// verbatim, so strings are quoted. An expression embedded in a longer plain
+// scalar is replaced by the input's text, and inputs that would change the
+// document structure there are rejected. Referenced inputs missing from the
+// map are reported together in the returned error.
+func (s *TemplatesService) ResolveTemplate(ctx context.Context, spaceRef, identifier string, inputs map[string]any) (string, *Response, error) {
+	template, resp, err := s.GetTemplate(ctx, spaceRef, identifier)
+	if err != nil {
+		return "", resp, err
+	}
+	if template.Data == nil {
+		return "", resp, fmt.Errorf("template %q has no data", identifier)
+	}
+
+	data := *template.Data
+	missing := make(map[string]bool)
+	var resolved strings.Builder
+	last := 0
+	for _, m := range templateInputReference.FindAllStringSubmatchIndex(data, -1) {
+		resolved.WriteString(data[last:m[0]])
+		last = m[1]
+
+		name := data[m[2]:m[3]]
+		value, ok := inputs[name]
+		if !ok {
+			missing[name] = true
+			resolved.WriteString(data[m[0]:m[1]])
+			continue
+		}
+		text, err := templateInputValue(value, isWholeYAMLValue(data, m[0], m[1]))
+		if err != nil {
+			return "", resp, fmt.Errorf("template %q: input %q: %w", identifier, name, err)
+		}
+		resolved.WriteString(text)
+	}
+	resolved.WriteString(data[last:])
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", resp, fmt.Errorf("template %q: missing inputs: %s", identifier, strings.Join(names, ", "))
+	}
+	return resolved.String(), resp, nil
+}
+
+// yamlValueStart matches a line prefix that ends where a mapping or sequence value starts
+var yamlValueStart = regexp.MustCompile(`(:|^\s*-)\s+$`)
+
+// isWholeYAMLValue reports whether data[start:end] is the entire value on its
+// line, i.e. it follows "key: " or "- " and is followed only by a comment
+func isWholeYAMLValue(data string, start, end int) bool {
+	before := data[strings.LastIndex(data[:start], "\n")+1 : start]
+	after := data[end:]
+	if i := strings.IndexByte(after, '\n'); i >= 0 {
+		after = after[:i]
+	}
+	after = strings.TrimSpace(after)
+	return yamlValueStart.MatchString(before) && (after == "" || strings.HasPrefix(after, "#"))
+}
+
+// templateInputValue renders an input value for splicing into template YAML.
+// Whole values are JSON encoded; embedded values must be plain text that
+// cannot end the scalar they are part of.
+func templateInputValue(value any, whole bool) (string, error) {
+	if whole {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	text := fmt.Sprint(value)
+	if strings.ContainsAny(text, "\n\r\"'") || strings.Contains(text, ": ") || strings.Contains(text, " #") {
+		return "", errors.New("value cannot be embedded in a larger YAML scalar")
+	}
+	return text, nil
+}
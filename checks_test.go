@@ -0,0 +1,280 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCreateChecks tests that results keep the input order and failures are reported per check
+func TestCreateChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/api/v1/repos/space%2Frepo/checks/commits/abc123" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		var opt CreateCheckOptions
+		if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if *opt.Identifier == "lint" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"invalid check"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(&Check{Identifier: opt.Identifier})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	identifiers := []string{"build", "lint", "test-linux", "test-darwin", "test-windows"}
+	var checks []*CreateCheckOptions
+	for _, id := range identifiers {
+		checks = append(checks, &CreateCheckOptions{Identifier: Ptr(id), Status: Ptr("success")})
+	}
+
+	results, err := client.Checks.CreateChecks(context.Background(), "space/repo", "abc123", checks)
+	if err == nil {
+		t.Fatal("Expected an error for the failed check")
+	}
+	if len(results) != len(identifiers) {
+		t.Fatalf("Expected %d results, got %d", len(identifiers), len(results))
+	}
+
+	for i, id := range identifiers {
+		if id == "lint" {
+			if results[i].Err == nil {
+				t.Errorf("Expected error for check %q", id)
+			}
+			continue
+		}
+		if results[i].Err != nil {
+			t.Errorf("Unexpected error for check %q: %v", id, results[i].Err)
+			continue
+		}
+		if *results[i].Check.Identifier != id {
+			t.Errorf("Expected result %d to be %q, got %q", i, id, *results[i].Check.Identifier)
+		}
+	}
+}
+
+// TestListChecks tests that checks are listed from the commit's status check path
+func TestListChecks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/checks/commits/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		if want := "/api/v1/repos/space%2Frepo/checks/commits/abc123"; r.URL.EscapedPath() != want {
+			t.Errorf("Expected escaped path %q, got %q", want, r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("latest") != "true" {
+			t.Errorf("Expected latest=true, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"identifier":"build","status":"success"}]`))
+	})
+	client := NewTestClient(mux)
+
+	checks, _, err := client.Checks.ListChecks(context.Background(), "space/repo", "abc123", &ListChecksOptions{Latest: Ptr(true)})
+	if err != nil {
+		t.Fatalf("ListChecks returned error: %v", err)
+	}
+	if len(checks) != 1 || *checks[0].Identifier != "build" || *checks[0].Status != "success" {
+		t.Errorf("Unexpected checks %+v", checks)
+	}
+}
+
+// TestCreateTemplateValidation tests that invalid template data is rejected before any request is sent
+func TestCreateTemplateValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var opt CreateTemplateOptions
+		if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Template{Identifier: opt.Identifier, Type: opt.Type})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	_, _, err = client.Templates.CreateTemplate(context.Background(), "space", &CreateTemplateOptions{
+		Identifier: Ptr("build"),
+		Data:       Ptr("stage:\n\ttype: ci\n"),
+		Type:       Ptr(TemplateTypeStage),
+		Validate:   Ptr(true),
+	})
+	var dataErr *TemplateDataError
+	if !errors.As(err, &dataErr) || dataErr.Line != 2 {
+		t.Fatalf("Expected TemplateDataError on line 2, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no requests, got %d", requests)
+	}
+
+	template, _, err := client.Templates.CreateTemplate(context.Background(), "space", &CreateTemplateOptions{
+		Identifier: Ptr("build"),
+		Data:       Ptr("# build stage\nstage:\n  type: ci\n"),
+		Type:       Ptr(TemplateTypeStage),
+		Validate:   Ptr(true),
+	})
+	if err != nil {
+		t.Fatalf("CreateTemplate returned error: %v", err)
+	}
+	if template.Type == nil || *template.Type != TemplateTypeStage {
+		t.Errorf("Expected type %q, got %v", TemplateTypeStage, template.Type)
+	}
+
+	if _, _, err := client.Templates.CreateTemplate(context.Background(), "space", &CreateTemplateOptions{
+		Identifier: Ptr("build"),
+		Data:       Ptr("- not a mapping\n"),
+	}); err != nil {
+		t.Errorf("Expected data to be sent unchecked without Validate, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestValidateTemplateData(t *testing.T) {
+	tests := []struct {
+		data    string
+		wantErr bool
+	}{
+		{"", true},
+		{"  \n", true},
+		{"- step\n", true},
+		{"just text\n", true},
+		{"stage:\n\tspec: {}\n", true},
+		{"stage:\n  spec: {}\n", false},
+		{"  stage:\n    spec: {}\n", false},
+		{"{stage: {spec: {}}}\n", false},
+		{"%YAML 1.2\n---\nstep:\n  type: run\n", false},
+		{"base: &base\n  type: run\nstep:\n  <<: *base\n  script: |\n    \techo ok\n", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateTemplateData(tt.data); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTemplateData(%q) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+		}
+	}
+}
+
+// TestResolveTemplate tests that input expressions are expanded and missing inputs are reported
+func TestResolveTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/templates/build") {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Template{
+			Identifier: Ptr("build"),
+			Data:       Ptr("stage:\n  image: ${{ inputs.image }}\n  parallel: ${{inputs.workers}}\n  tag: ${{ inputs.tag }}\n"),
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	data, _, err := client.Templates.ResolveTemplate(context.Background(), "space", "build", map[string]any{
+		"image":   "golang:1.22",
+		"workers": 4,
+		"tag":     "latest",
+	})
+	if err != nil {
+		t.Fatalf("ResolveTemplate returned error: %v", err)
+	}
+	want := "stage:\n  image: \"golang:1.22\"\n  parallel: 4\n  tag: \"latest\"\n"
+	if data != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	_, _, err = client.Templates.ResolveTemplate(context.Background(), "space", "build", map[string]any{"image": "alpine"})
+	if err == nil || !strings.Contains(err.Error(), "missing inputs: tag, workers") {
+		t.Errorf("Expected missing inputs error, got %v", err)
+	}
+}
+
+// TestResolveTemplateQuotesInputs tests that input values cannot change the structure of the template
+func TestResolveTemplateQuotesInputs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/spaces/{space}/templates/{template}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Template{
+			Data: Ptr("run: ${{ inputs.script }} # the script\nimage: registry/app-${{ inputs.image }}:1\nurl: http:${{ inputs.image }}\nargs:\n  - ${{ inputs.flag }}\n"),
+		})
+	})
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	data, _, err := client.Templates.ResolveTemplate(ctx, "space", "build", map[string]any{
+		"script": "echo a: b\nsteps: []",
+		"image":  "alpine",
+		"flag":   "-v #1",
+	})
+	if err != nil {
+		t.Fatalf("ResolveTemplate returned error: %v", err)
+	}
+	want := "run: \"echo a: b\\nsteps: []\" # the script\nimage: registry/app-alpine:1\nurl: http:alpine\nargs:\n  - \"-v #1\"\n"
+	if data != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	_, _, err = client.Templates.ResolveTemplate(ctx, "space", "build", map[string]any{
+		"script": "true",
+		"image":  "alpine # latest",
+		"flag":   "-v",
+	})
+	if err == nil || !strings.Contains(err.Error(), `input "image"`) {
+		t.Errorf("Expected an error for an unsafe embedded value, got %v", err)
+	}
+}
+
+func TestCopyTemplateAndDelete(t *testing.T) {
+	var created map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/spaces/team/templates/build", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identifier":"build","type":"stage","data":"stage:\n  type: ci\n"}`))
+	})
+	mux.HandleFunc("POST /api/v1/spaces/org/templates", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&created)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"identifier":"build","type":"stage"}`))
+	})
+	mux.HandleFunc("DELETE /api/v1/spaces/team/templates/build", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := NewTestClient(mux)
+
+	template, _, err := client.Templates.CopyTemplateAndDelete(context.Background(), "team/build", "org")
+	if err != nil {
+		t.Fatalf("CopyTemplateAndDelete returned error: %v", err)
+	}
+	if *template.Identifier != "build" {
+		t.Errorf("identifier = %q", *template.Identifier)
+	}
+	if created["type"] != "stage" || created["data"] != "stage:\n  type: ci\n" {
+		t.Errorf("create body = %v", created)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 )
 
@@ -38,7 +39,7 @@ type UploadCiCacheRequest struct {
 func (s *CiCacheService) UploadCiCache(ctx context.Context, key string, version int, data io.Reader) (*CiCacheEntry, *Response, error) {
 	path := fmt.Sprintf("ci/cache/%s", url.PathEscape(key))
 
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx)
 	if version > 0 {
 		req.SetQueryParam("version", fmt.Sprintf("%d", version))
 	}
@@ -68,10 +69,12 @@ type GetCiCacheOptions struct {
 	Version *int `url:"version,omitempty"`
 }
 
-// GetCiCache retrieves a CI cache entry by key
+// GetCiCache retrieves a CI cache entry by key. The content is streamed: the
+// caller must close the returned reader, and canceling ctx aborts a read in
+// progress.
 func (s *CiCacheService) GetCiCache(ctx context.Context, key string, opt *GetCiCacheOptions) (io.ReadCloser, *Response, error) {
 	path := fmt.Sprintf("ci/cache/%s", url.PathEscape(key))
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx).DisableAutoReadResponse()
 
 	if opt != nil && opt.Version != nil {
 		req.SetQueryParam("version", fmt.Sprintf("%d", *opt.Version))
@@ -83,11 +86,64 @@ func (s *CiCacheService) GetCiCache(ctx context.Context, key string, opt *GetCiC
 		return nil, &Response{Response: resp}, err
 	}
 
+	if !resp.IsSuccessState() {
+		defer resp.Body.Close()
+		if _, err := resp.ToBytes(); err != nil {
+			return nil, &Response{Response: resp}, err
+		}
+		return nil, &Response{Response: resp}, s.client.checkResponse(resp)
+	}
+
+	return newContextBody(ctx, resp.Body), &Response{Response: resp}, nil
+}
+
+// HeadCiCache retrieves the metadata of a CI cache entry without downloading
+// it. Size is taken from Content-Length and Created from Last-Modified when
+// the server sends them; a missing entry results in a 404 error.
+func (s *CiCacheService) HeadCiCache(ctx context.Context, key string, opt *GetCiCacheOptions) (*CiCacheEntry, *Response, error) {
+	path := fmt.Sprintf("ci/cache/%s", url.PathEscape(key))
+	req := s.client.newRequest(ctx)
+
+	if opt != nil && opt.Version != nil {
+		req.SetQueryParam("version", fmt.Sprintf("%d", *opt.Version))
+	}
+
+	resp, err := req.Head(s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
 	if err := s.client.checkResponse(resp); err != nil {
 		return nil, &Response{Response: resp}, err
 	}
 
-	return resp.Body, &Response{Response: resp}, nil
+	entry := &CiCacheEntry{Key: Ptr(key)}
+	if resp.ContentLength >= 0 {
+		entry.Size = Ptr(resp.ContentLength)
+	}
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		entry.Created = Ptr(Time(lastModified))
+	}
+
+	return entry, &Response{Response: resp}, nil
+}
+
+// CiCacheExists reports whether a CI cache entry exists. A missing entry is
+// reported as false rather than an error. A version of 0 checks the latest.
+func (s *CiCacheService) CiCacheExists(ctx context.Context, key string, version int) (bool, *Response, error) {
+	var opt *GetCiCacheOptions
+	if version > 0 {
+		opt = &GetCiCacheOptions{Version: Ptr(version)}
+	}
+
+	_, resp, err := s.HeadCiCache(ctx, key, opt)
+	if isNotFound(err) {
+		return false, resp, nil
+	}
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
 }
 
 // ListCiCacheOptions specifies optional parameters for listing CI cache entries
@@ -98,19 +154,21 @@ type ListCiCacheOptions struct {
 
 // ListCiCache lists CI cache entries with optional filtering
 func (s *CiCacheService) ListCiCache(ctx context.Context, opt *ListCiCacheOptions) ([]*CiCacheEntry, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+	if opt == nil {
+		opt = &ListCiCacheOptions{}
+	}
 
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
-		if opt.KeyPrefix != nil {
-			req.SetQueryParam("key_prefix", *opt.KeyPrefix)
-		}
+	req := s.client.newRequest(ctx)
+
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.KeyPrefix != nil {
+		req.SetQueryParam("key_prefix", *opt.KeyPrefix)
 	}
 
 	var entries []*CiCacheEntry
 	req.SetSuccessResult(&entries)
 
-	resp, err := req.Get("ci/cache")
+	resp, err := req.Get(s.client.buildFullURL("ci/cache"))
 	if err != nil {
 		return nil, &Response{Response: resp}, err
 	}
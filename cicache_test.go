@@ -0,0 +1,127 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHeadCiCache tests that cache metadata is read from HEAD response headers
+func TestHeadCiCache(t *testing.T) {
+	modified := time.Date(2025, 5, 4, 10, 30, 0, 0, time.UTC)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected HEAD, got %s", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.EscapedPath(), "/ci/cache/go-mod%2Fv1") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "52428800")
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	})
+	client := NewTestClient(handler)
+
+	entry, _, err := client.CiCache.HeadCiCache(context.Background(), "go-mod/v1", nil)
+	if err != nil {
+		t.Fatalf("HeadCiCache returned error: %v", err)
+	}
+	if entry.Size == nil || *entry.Size != 52428800 {
+		t.Errorf("Expected size 52428800, got %v", entry.Size)
+	}
+	if entry.Created == nil || !time.Time(*entry.Created).Equal(modified) {
+		t.Errorf("Expected created %v, got %v", modified, entry.Created)
+	}
+
+	if _, _, err := client.CiCache.HeadCiCache(context.Background(), "missing", nil); !isNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+// TestCiCacheExists tests that a missing entry is reported as false and other failures as errors
+func TestCiCacheExists(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/ci/cache/hit"):
+			if got := r.URL.Query().Get("version"); got != "2" {
+				t.Errorf("Expected version 2, got %q", got)
+			}
+			w.WriteHeader(http.StatusOK)
+		case strings.HasSuffix(r.URL.Path, "/ci/cache/forbidden"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client := NewTestClient(handler)
+	ctx := context.Background()
+
+	if exists, _, err := client.CiCache.CiCacheExists(ctx, "hit", 2); err != nil || !exists {
+		t.Errorf("Expected hit to exist, got %v, %v", exists, err)
+	}
+	if exists, _, err := client.CiCache.CiCacheExists(ctx, "miss", 0); err != nil || exists {
+		t.Errorf("Expected miss not to exist without error, got %v, %v", exists, err)
+	}
+	if _, _, err := client.CiCache.CiCacheExists(ctx, "forbidden", 0); err == nil {
+		t.Error("Expected error for forbidden cache entry")
+	}
+}
+
+// TestGetCiCacheCancel tests that canceling the context unblocks a stalled download
+func TestGetCiCacheCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	body, _, err := client.CiCache.GetCiCache(ctx, "go-mod", nil)
+	if err != nil {
+		t.Fatalf("GetCiCache returned error: %v", err)
+	}
+	defer body.Close()
+
+	done := make(chan error, 1)
+	var buf bytes.Buffer
+	go func() {
+		_, err := io.Copy(&buf, body)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if buf.String() != "partial" {
+			t.Errorf("Expected the partial content to be read, got %q", buf.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("io.Copy did not return after the context was canceled")
+	}
+}
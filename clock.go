@@ -0,0 +1,38 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock is the source of time for helpers that compare against the current
+// time or wait between polls. Tests can inject a fake with WithClock to run
+// polling loops deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the default Clock backed by the time package
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets the clock used by polling and time-based helpers such as
+// WaitForGitspaceState, RestartGitspace and ListStaleBranches
+func WithClock(clock Clock) ClientOptionFunc {
+	return func(c *Client) error {
+		if clock == nil {
+			return fmt.Errorf("clock must not be nil")
+		}
+		c.clock = clock
+		return nil
+	}
+}
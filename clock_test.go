@@ -0,0 +1,76 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock reports a fixed time and fires every wait immediately, recording its duration
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// TestWithClock tests that polling waits and the current time come from the injected clock
+func TestWithClock(t *testing.T) {
+	states := []GitspaceState{GitspaceStateStarting, GitspaceStateStarting, GitspaceStateRunning}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/branches") {
+			w.Write([]byte(`[{"name": "old", "commit": {"committer": {"when": "2025-01-01T00:00:00Z"}}}]`))
+			return
+		}
+		json.NewEncoder(w).Encode(&Gitspace{State: Ptr(states[polls])})
+		polls++
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithClock(clock))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.Gitspaces.WaitForGitspaceState(ctx, "dev", GitspaceStateRunning, time.Hour); err != nil {
+		t.Fatalf("WaitForGitspaceState returned error: %v", err)
+	}
+	if len(clock.waits) != 2 || clock.waits[0] != time.Hour {
+		t.Errorf("Expected two hour-long waits, got %v", clock.waits)
+	}
+
+	// Two hours of fake waits passed; the branch is 9 days old
+	stale, err := client.Repositories.ListStaleBranches(ctx, "space/repo", 7*24*time.Hour, nil)
+	if err != nil {
+		t.Fatalf("ListStaleBranches returned error: %v", err)
+	}
+	if len(stale) != 1 {
+		t.Errorf("Expected the branch to be stale relative to the fake clock, got %d", len(stale))
+	}
+
+	if _, err := NewClient("test-token", WithClock(nil)); err == nil {
+		t.Error("Expected error for a nil clock")
+	}
+}
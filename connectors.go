@@ -8,8 +8,13 @@ package gitness
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 )
 
 // ConnectorsService handles communication with connector related methods
@@ -40,13 +45,46 @@ type ConnectorAuthType string
 
 // Connector auth types
 const (
+	ConnectorAuthTypeBasic  ConnectorAuthType = "basic"
 	ConnectorAuthTypeBearer ConnectorAuthType = "bearer"
 )
 
 // ConnectorAuth represents connector authentication credentials
 type ConnectorAuth struct {
-	AuthType ConnectorAuthType `json:"auth_type,omitempty"`
-	Token    *string           `json:"token,omitempty"`
+	Type   *ConnectorAuthType      `json:"type,omitempty"`
+	Basic  *BasicAuthCredentials   `json:"basic,omitempty"`
+	Bearer *BearerTokenCredentials `json:"bearer,omitempty"`
+}
+
+// SecretReference refers to a secret by identifier in the space of the resource using it
+type SecretReference struct {
+	Identifier *string `json:"identifier,omitempty"`
+}
+
+// BasicAuthCredentials holds a username and a reference to the password secret
+type BasicAuthCredentials struct {
+	Username *string          `json:"username,omitempty"`
+	Password *SecretReference `json:"password,omitempty"`
+}
+
+// BearerTokenCredentials holds a reference to the token secret
+type BearerTokenCredentials struct {
+	Token *SecretReference `json:"token,omitempty"`
+}
+
+// secretIdentifiers returns the identifiers of the secrets the credentials refer to
+func (a *ConnectorAuth) secretIdentifiers() []string {
+	if a == nil {
+		return nil
+	}
+	var ids []string
+	if a.Basic != nil && a.Basic.Password != nil && a.Basic.Password.Identifier != nil {
+		ids = append(ids, *a.Basic.Password.Identifier)
+	}
+	if a.Bearer != nil && a.Bearer.Token != nil && a.Bearer.Token.Identifier != nil {
+		ids = append(ids, *a.Bearer.Token.Identifier)
+	}
+	return ids
 }
 
 // GithubConnectorData represents github connector specific data
@@ -71,6 +109,19 @@ type Connector struct {
 	Updated          *int64               `json:"updated,omitempty"`
 }
 
+// IsHealthy reports whether the last connection test of the connector succeeded
+func (c *Connector) IsHealthy() bool {
+	return c.LastTestStatus != nil && *c.LastTestStatus == ConnectorStatusOK
+}
+
+// LastTested returns when the connector was last tested, or the zero time if it never was
+func (c *Connector) LastTested() time.Time {
+	if c.LastTestAttempt == nil || *c.LastTestAttempt <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(*c.LastTestAttempt)
+}
+
 // CreateConnectorOptions specifies options for creating a connector based on OpenapiCreateConnectorRequest schema
 type CreateConnectorOptions struct {
 	Description *string              `json:"description,omitempty"`
@@ -86,13 +137,41 @@ type UpdateConnectorOptions struct {
 	Github      *GithubConnectorData `json:"github,omitempty"`
 }
 
-// ListConnectors lists all connectors
-func (s *ConnectorsService) ListConnectors(ctx context.Context, opt *ListOptions) ([]*Connector, *Response, error) {
+// ListConnectorsOptions specifies options for listing connectors
+type ListConnectorsOptions struct {
+	ListOptions
+	SpaceRef *string        `url:"-"`
+	Type     *ConnectorType `url:"-"`
+}
+
+// ListConnectors lists connectors. With SpaceRef set only the connectors of
+// that space are listed. Type is not supported by the API and is applied to
+// the returned page client-side.
+func (s *ConnectorsService) ListConnectors(ctx context.Context, opt *ListConnectorsOptions) ([]*Connector, *Response, error) {
+	path := "connectors"
+	var listOpt *ListOptions
+	if opt != nil {
+		listOpt = &opt.ListOptions
+		if opt.SpaceRef != nil {
+			path = fmt.Sprintf("spaces/%s/connectors", url.PathEscape(*opt.SpaceRef))
+		}
+	}
+
 	var connectors []*Connector
-	resp, err := s.client.performListRequest(ctx, "connectors", opt, &connectors)
+	resp, err := s.client.performListRequest(ctx, path, listOpt, &connectors)
 	if err != nil {
 		return nil, resp, err
 	}
+
+	if opt != nil && opt.Type != nil {
+		filtered := connectors[:0]
+		for _, connector := range connectors {
+			if connector.Type != nil && *connector.Type == *opt.Type {
+				filtered = append(filtered, connector)
+			}
+		}
+		connectors = filtered
+	}
 	return connectors, resp, nil
 }
 
@@ -134,3 +213,201 @@ func (s *ConnectorsService) DeleteConnector(ctx context.Context, connectorRef st
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
+
+// splitSpaceRef splits a space-scoped resource reference such as
+// "space/identifier" into the space reference and the identifier
+func splitSpaceRef(kind, ref string) (string, string, error) {
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", fmt.Errorf("%s reference %q must be of the form space/identifier", kind, ref)
+	}
+	return ref[:i], ref[i+1:], nil
+}
+
+// CopyConnectorAndDelete recreates a connector under targetSpaceRef with the
+// same identifier and configuration, then deletes the original. Gitness has
+// no move endpoint, so this is not atomic: when the delete fails both copies
+// exist and the new connector is returned alongside the error.
+//
+// The secrets a connector authenticates with are referenced by identifier
+// relative to its space, so each one is looked up in targetSpaceRef first
+// and nothing is changed if any of them is missing there.
+func (s *ConnectorsService) CopyConnectorAndDelete(ctx context.Context, connectorRef, targetSpaceRef string) (*Connector, *Response, error) {
+	if _, _, err := splitSpaceRef("connector", connectorRef); err != nil {
+		return nil, nil, err
+	}
+	connector, resp, err := s.GetConnector(ctx, connectorRef)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if connector.Github != nil {
+		for _, id := range connector.Github.Auth.secretIdentifiers() {
+			if _, resp, err := s.client.Secrets.GetSecret(ctx, targetSpaceRef+"/"+id); err != nil {
+				return nil, resp, fmt.Errorf("connector %q uses secret %q, not found in %q: %w", connectorRef, id, targetSpaceRef, err)
+			}
+		}
+	}
+
+	copied, resp, err := s.CreateConnector(ctx, &CreateConnectorOptions{
+		Description: connector.Description,
+		Github:      connector.Github,
+		Identifier:  connector.Identifier,
+		SpaceRef:    Ptr(targetSpaceRef),
+		Type:        connector.Type,
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp, err := s.DeleteConnector(ctx, connectorRef); err != nil {
+		return copied, resp, fmt.Errorf("delete original connector %q: %w", connectorRef, err)
+	}
+	return copied, resp, nil
+}
+
+// ConnectorUsageType identifies the kind of resource referencing a connector
+type ConnectorUsageType string
+
+// Connector usage types
+const (
+	ConnectorUsageTypePipeline ConnectorUsageType = "pipeline"
+	ConnectorUsageTypeTemplate ConnectorUsageType = "template"
+)
+
+// ConnectorUsage describes a pipeline or template that references a connector
+type ConnectorUsage struct {
+	Type       ConnectorUsageType
+	SpaceRef   string
+	RepoPath   string
+	Identifier string
+	ConfigPath string
+}
+
+// connectorReference matches YAML keys such as "connector: id" or "connectorRef: id"
+func connectorReference(identifier string) *regexp.Regexp {
+	return regexp.MustCompile(`(?mi)^\s*-?\s*connector\w*\s*:\s*["']?` + regexp.QuoteMeta(identifier) + `["']?\s*$`)
+}
+
+// connectorScanConcurrency bounds the pipeline configs ListConnectorUsages fetches at once
+const connectorScanConcurrency = 8
+
+// ListConnectorUsages finds the templates of the connector's space and the
+// pipelines of the space's repositories whose YAML references the connector.
+//
+// This is a heuristic. Gitness does not track connector references, so every
+// template and pipeline configuration in the space is fetched, one request
+// per pipeline with at most connectorScanConcurrency in flight, and matched
+// line by line against keys such as "connector: <identifier>". References
+// made through template inputs or expressions are missed, and a matching
+// line inside a block scalar is reported as a usage.
+func (s *ConnectorsService) ListConnectorUsages(ctx context.Context, connectorRef string) ([]*ConnectorUsage, *Response, error) {
+	spaceRef, identifier, err := splitSpaceRef("connector", connectorRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	pattern := connectorReference(identifier)
+
+	var usages []*ConnectorUsage
+
+	templates, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*Template, *Response, error) {
+		return s.client.Templates.ListTemplates(ctx, spaceRef, &ListOptions{Page: Ptr(page), Limit: Ptr(100)})
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, template := range templates {
+		if template.Identifier == nil {
+			continue
+		}
+		if template.Data == nil {
+			if template, resp, err = s.client.Templates.GetTemplate(ctx, spaceRef, *template.Identifier); err != nil {
+				return nil, resp, err
+			}
+		}
+		if template.Data != nil && pattern.MatchString(*template.Data) {
+			usages = append(usages, &ConnectorUsage{
+				Type:       ConnectorUsageTypeTemplate,
+				SpaceRef:   spaceRef,
+				Identifier: *template.Identifier,
+			})
+		}
+	}
+
+	repositories, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*Repository, *Response, error) {
+		return s.client.Spaces.ListRepositories(ctx, spaceRef, &ListRepositoriesOptions{
+			ListOptions: ListOptions{Page: Ptr(page), Limit: Ptr(100)},
+			Recursive:   Ptr(true),
+		})
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var candidates []*ConnectorUsage
+	var branches []*string
+	for _, repository := range repositories {
+		if repository.Path == nil {
+			continue
+		}
+		repoPath := *repository.Path
+
+		pipelines, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*Pipeline, *Response, error) {
+			return s.client.Pipelines.ListPipelines(ctx, repoPath, &ListOptions{Page: Ptr(page), Limit: Ptr(100)})
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+		for _, pipeline := range pipelines {
+			if pipeline.Identifier == nil || pipeline.ConfigPath == nil {
+				continue
+			}
+			candidates = append(candidates, &ConnectorUsage{
+				Type:       ConnectorUsageTypePipeline,
+				SpaceRef:   spaceRef,
+				RepoPath:   repoPath,
+				Identifier: *pipeline.Identifier,
+				ConfigPath: *pipeline.ConfigPath,
+			})
+			branches = append(branches, pipeline.DefaultBranch)
+		}
+	}
+
+	matched := make([]bool, len(candidates))
+	errs := make([]error, len(candidates))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(connectorScanConcurrency, len(candidates)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				c := candidates[i]
+				config, _, err := s.client.Repositories.GetRawFile(ctx, c.RepoPath, c.ConfigPath, branches[i])
+				if err != nil {
+					if !isNotFound(err) {
+						errs[i] = fmt.Errorf("pipeline %q in %q: %w", c.Identifier, c.RepoPath, err)
+					}
+					continue
+				}
+				matched[i] = pattern.Match(config)
+			}
+		}()
+	}
+	for i := range candidates {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, resp, err
+	}
+	for i, c := range candidates {
+		if matched[i] {
+			usages = append(usages, c)
+		}
+	}
+	return usages, resp, nil
+}
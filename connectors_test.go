@@ -0,0 +1,175 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestListConnectorsInSpace tests space scoping, query passing and type filtering
+func TestListConnectorsInSpace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/connectors") || !strings.Contains(r.URL.Path, "/spaces/") {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("query"); got != "ci" {
+			t.Errorf("Expected query ci, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"identifier":"ci-github","type":"github"},{"identifier":"ci-other","type":"other"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	connectors, _, err := client.Connectors.ListConnectors(context.Background(), &ListConnectorsOptions{
+		ListOptions: ListOptions{Query: Ptr("ci")},
+		SpaceRef:    Ptr("team"),
+		Type:        Ptr(ConnectorTypeGithub),
+	})
+	if err != nil {
+		t.Fatalf("ListConnectors returned error: %v", err)
+	}
+
+	if len(connectors) != 1 || *connectors[0].Identifier != "ci-github" {
+		t.Errorf("Expected only the github connector, got %d connectors", len(connectors))
+	}
+}
+
+// TestListConnectorUsages tests that templates and pipeline configs referencing the connector are reported
+func TestListConnectorUsages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/templates"):
+			w.Write([]byte(`[{"identifier":"build","data":"steps:\n  - connector: gh\n"},{"identifier":"lint","data":"connector: ghx\n"}]`))
+		case strings.HasSuffix(r.URL.Path, "/repos"):
+			if got := r.URL.Query().Get("recursive"); got != "true" {
+				t.Errorf("Expected recursive=true, got %q", got)
+			}
+			w.Write([]byte(`[{"path":"team/app"}]`))
+		case strings.HasSuffix(r.URL.Path, "/pipelines"):
+			w.Write([]byte(`[{"identifier":"ci","config_path":".harness/ci.yaml"},{"identifier":"docs","config_path":".harness/docs.yaml"}]`))
+		case strings.HasSuffix(r.URL.Path, "/ci.yaml"):
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("spec:\n  connectorRef: \"gh\"\n"))
+		case strings.HasSuffix(r.URL.Path, "/docs.yaml"):
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("spec:\n  image: alpine\n"))
+		default:
+			t.Errorf("Unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	usages, _, err := client.Connectors.ListConnectorUsages(context.Background(), "team/gh")
+	if err != nil {
+		t.Fatalf("ListConnectorUsages returned error: %v", err)
+	}
+
+	if len(usages) != 2 {
+		t.Fatalf("Expected 2 usages, got %d", len(usages))
+	}
+	if usages[0].Type != ConnectorUsageTypeTemplate || usages[0].Identifier != "build" {
+		t.Errorf("Unexpected template usage: %+v", usages[0])
+	}
+	if usages[1].Type != ConnectorUsageTypePipeline || usages[1].RepoPath != "team/app" || usages[1].Identifier != "ci" {
+		t.Errorf("Unexpected pipeline usage: %+v", usages[1])
+	}
+}
+
+// TestConnectorHealth tests interpreting the last test status and timestamp
+func TestConnectorHealth(t *testing.T) {
+	tested := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	healthy := &Connector{
+		LastTestStatus:  Ptr(ConnectorStatusOK),
+		LastTestAttempt: Ptr(tested.UnixMilli()),
+	}
+	if !healthy.IsHealthy() {
+		t.Error("Expected connector with ok status to be healthy")
+	}
+	if got := healthy.LastTested(); !got.Equal(tested) {
+		t.Errorf("Expected last tested %v, got %v", tested, got)
+	}
+
+	for _, c := range []*Connector{{}, {LastTestStatus: Ptr(ConnectorStatusError)}, {LastTestStatus: Ptr(ConnectorStatusPending)}} {
+		if c.IsHealthy() {
+			t.Errorf("Expected connector with status %v to be unhealthy", c.LastTestStatus)
+		}
+		if !c.LastTested().IsZero() {
+			t.Errorf("Expected zero last tested time, got %v", c.LastTested())
+		}
+	}
+}
+
+func TestCopyConnectorAndDelete(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/connectors/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identifier":"gh","type":"github","github":{"api_url":"https://api.github.com"}}`))
+	})
+	mux.HandleFunc("POST /api/v1/connectors", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"identifier":"gh","type":"github"}`))
+	})
+	mux.HandleFunc("DELETE /api/v1/connectors/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"forbidden"}`))
+	})
+	client := NewTestClient(mux)
+
+	connector, _, err := client.Connectors.CopyConnectorAndDelete(context.Background(), "team/gh", "org")
+	if err == nil || !strings.Contains(err.Error(), "delete original connector") {
+		t.Fatalf("expected delete error, got %v", err)
+	}
+	if connector == nil || *connector.Identifier != "gh" {
+		t.Errorf("expected the new connector alongside the error, got %+v", connector)
+	}
+}
+
+// TestCopyConnectorAndDeleteMissingSecret tests that a connector is not copied to a space missing its secrets
+func TestCopyConnectorAndDeleteMissingSecret(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/connectors/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identifier":"gh","type":"github","github":{"auth":{"type":"bearer","bearer":{"token":{"identifier":"gh-token"}}}}}`))
+	})
+	mux.HandleFunc("GET /api/v1/secrets/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.PathValue("ref"); got != "org/gh-token" {
+			t.Errorf("secret ref = %q, want org/gh-token", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	})
+	mux.HandleFunc("POST /api/v1/connectors", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("connector must not be created")
+	})
+	client := NewTestClient(mux)
+
+	_, _, err := client.Connectors.CopyConnectorAndDelete(context.Background(), "team/gh", "org")
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), `"gh-token"`) {
+		t.Errorf("expected a missing secret error, got %v", err)
+	}
+}
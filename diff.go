@@ -0,0 +1,224 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileDiffStatus represents how a file changed in a diff
+type FileDiffStatus string
+
+// FileDiffStatus constants
+const (
+	FileDiffStatusAdded    FileDiffStatus = "ADDED"
+	FileDiffStatusModified FileDiffStatus = "MODIFIED"
+	FileDiffStatusDeleted  FileDiffStatus = "DELETED"
+	FileDiffStatusRenamed  FileDiffStatus = "RENAMED"
+	FileDiffStatusCopied   FileDiffStatus = "COPIED"
+)
+
+// DiffLineType represents the kind of a line within a hunk
+type DiffLineType string
+
+// DiffLineType constants
+const (
+	DiffLineContext DiffLineType = "context"
+	DiffLineAdded   DiffLineType = "added"
+	DiffLineDeleted DiffLineType = "deleted"
+)
+
+// DiffHunk represents a contiguous block of changes in a file
+type DiffHunk struct {
+	OldStart int         `json:"old_start"`
+	OldLines int         `json:"old_lines"`
+	NewStart int         `json:"new_start"`
+	NewLines int         `json:"new_lines"`
+	Header   string      `json:"header,omitempty"`
+	Lines    []*DiffLine `json:"lines,omitempty"`
+}
+
+// DiffLine represents a single line of a hunk. OldLineNo is 0 for added
+// lines and NewLineNo is 0 for deleted lines.
+type DiffLine struct {
+	Type      DiffLineType `json:"type"`
+	Content   string       `json:"content"`
+	OldLineNo int          `json:"old_line_no,omitempty"`
+	NewLineNo int          `json:"new_line_no,omitempty"`
+}
+
+// hunkHeader matches "@@ -old_start[,old_lines] +new_start[,new_lines] @@ section"
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// ParseUnifiedDiff parses unified diff text, such as the output of
+// GetCommitDiff, into per-file hunks with line numbers. Both git-style diffs
+// ("diff --git" headers) and plain "---"/"+++" diffs are accepted.
+func ParseUnifiedDiff(s string) ([]*FileDiff, error) {
+	var (
+		files []*FileDiff
+		file  *FileDiff
+		hunk  *DiffHunk
+		// lines still expected in the current hunk
+		oldLeft, newLeft int
+		oldNo, newNo     int
+	)
+
+	startFile := func() {
+		file = &FileDiff{Status: Ptr(FileDiffStatusModified), Additions: Ptr(0), Deletions: Ptr(0)}
+		files = append(files, file)
+		hunk = nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			var dl *DiffLine
+			switch {
+			case strings.HasPrefix(line, "+"):
+				dl = &DiffLine{Type: DiffLineAdded, Content: line[1:], NewLineNo: newNo}
+				newNo++
+				newLeft--
+				*file.Additions++
+			case strings.HasPrefix(line, "-"):
+				dl = &DiffLine{Type: DiffLineDeleted, Content: line[1:], OldLineNo: oldNo}
+				oldNo++
+				oldLeft--
+				*file.Deletions++
+			case strings.HasPrefix(line, " "), line == "":
+				content := ""
+				if line != "" {
+					content = line[1:]
+				}
+				dl = &DiffLine{Type: DiffLineContext, Content: content, OldLineNo: oldNo, NewLineNo: newNo}
+				oldNo++
+				newNo++
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+				continue
+			default:
+				return nil, fmt.Errorf("parse diff: line %d: unexpected line in hunk: %q", i+1, line)
+			}
+			hunk.Lines = append(hunk.Lines, dl)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			startFile()
+			if oldPath, newPath, ok := parseGitDiffPaths(strings.TrimPrefix(line, "diff --git ")); ok {
+				file.OldPath = Ptr(oldPath)
+				file.Path = Ptr(newPath)
+			}
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			if file == nil || file.Hunks != nil {
+				startFile()
+			}
+			oldPath := diffPath(strings.TrimPrefix(line, "--- "), "a/")
+			newPath := diffPath(strings.TrimPrefix(lines[i+1], "+++ "), "b/")
+			switch {
+			case oldPath == "":
+				file.Status = Ptr(FileDiffStatusAdded)
+			case newPath == "":
+				file.Status = Ptr(FileDiffStatusDeleted)
+			}
+			if oldPath != "" {
+				file.OldPath = Ptr(oldPath)
+			}
+			if newPath != "" {
+				file.Path = Ptr(newPath)
+			} else {
+				file.Path = Ptr(oldPath)
+			}
+			i++
+		case strings.HasPrefix(line, "@@ "):
+			if file == nil {
+				return nil, fmt.Errorf("parse diff: line %d: hunk before file header", i+1)
+			}
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("parse diff: line %d: malformed hunk header: %q", i+1, line)
+			}
+			hunk = &DiffHunk{
+				OldStart: atoiDefault(m[1], 0),
+				OldLines: atoiDefault(m[2], 1),
+				NewStart: atoiDefault(m[3], 0),
+				NewLines: atoiDefault(m[4], 1),
+				Header:   m[5],
+			}
+			file.Hunks = append(file.Hunks, hunk)
+			oldLeft, newLeft = hunk.OldLines, hunk.NewLines
+			oldNo, newNo = hunk.OldStart, hunk.NewStart
+		case file == nil:
+			// Preamble such as a commit message before the first file
+		case strings.HasPrefix(line, "new file mode"):
+			file.Status = Ptr(FileDiffStatusAdded)
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.Status = Ptr(FileDiffStatusDeleted)
+		case strings.HasPrefix(line, "rename from "):
+			file.Status = Ptr(FileDiffStatusRenamed)
+			file.OldPath = Ptr(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			file.Path = Ptr(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "copy from "):
+			file.Status = Ptr(FileDiffStatusCopied)
+			file.OldPath = Ptr(strings.TrimPrefix(line, "copy from "))
+		case strings.HasPrefix(line, "copy to "):
+			file.Path = Ptr(strings.TrimPrefix(line, "copy to "))
+		case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+			file.IsBinary = Ptr(true)
+		}
+	}
+
+	if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+		return nil, fmt.Errorf("parse diff: unexpected end of input in hunk %q", hunk.Header)
+	}
+
+	for _, f := range files {
+		f.Changes = Ptr(*f.Additions + *f.Deletions)
+	}
+	return files, nil
+}
+
+// parseGitDiffPaths splits the "a/old b/new" part of a "diff --git" line
+func parseGitDiffPaths(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "a/") {
+		return "", "", false
+	}
+	idx := strings.Index(s, " b/")
+	if idx < 0 {
+		return "", "", false
+	}
+	return s[len("a/"):idx], s[idx+len(" b/"):], true
+}
+
+// diffPath strips prefix from a "---"/"+++" path and returns "" for /dev/null
+func diffPath(s, prefix string) string {
+	// Some tools append a tab-separated timestamp
+	if idx := strings.IndexByte(s, '\t'); idx >= 0 {
+		s = s[:idx]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// atoiDefault parses s, returning def when s is empty
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
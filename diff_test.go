@@ -0,0 +1,121 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleUnifiedDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@ package main
+ package main
+-
+-import "fmt"
++import "os"
++
+@@ -10,2 +10,3 @@ func main() {
+ 	x := 1
++	y := 2
+ 	return
+diff --git a/NEW.md b/NEW.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/NEW.md
+@@ -0,0 +1 @@
++-- not a file header
+\ No newline at end of file
+diff --git a/old.txt b/renamed.txt
+similarity index 100%
+rename from old.txt
+rename to renamed.txt
+diff --git a/logo.png b/logo.png
+index 4444444..5555555 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+// TestParseUnifiedDiff tests parsing a multi-file git diff into hunks and lines
+func TestParseUnifiedDiff(t *testing.T) {
+	files, err := ParseUnifiedDiff(sampleUnifiedDiff)
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff returned error: %v", err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("Expected 4 files, got %d", len(files))
+	}
+
+	modified := files[0]
+	if *modified.Path != "main.go" || *modified.Status != FileDiffStatusModified {
+		t.Errorf("Unexpected modified file: path %q, status %q", *modified.Path, *modified.Status)
+	}
+	if len(modified.Hunks) != 2 {
+		t.Fatalf("Expected 2 hunks, got %d", len(modified.Hunks))
+	}
+	first := modified.Hunks[0]
+	if first.OldStart != 1 || first.OldLines != 3 || first.NewStart != 1 || first.NewLines != 3 || first.Header != "package main" {
+		t.Errorf("Unexpected hunk header: %+v", first)
+	}
+	if len(first.Lines) != 5 {
+		t.Fatalf("Expected 5 lines in first hunk, got %d", len(first.Lines))
+	}
+	if l := first.Lines[2]; l.Type != DiffLineDeleted || l.Content != `import "fmt"` || l.OldLineNo != 3 || l.NewLineNo != 0 {
+		t.Errorf("Unexpected deleted line: %+v", l)
+	}
+	if l := first.Lines[3]; l.Type != DiffLineAdded || l.Content != `import "os"` || l.NewLineNo != 2 || l.OldLineNo != 0 {
+		t.Errorf("Unexpected added line: %+v", l)
+	}
+	if l := modified.Hunks[1].Lines[2]; l.Type != DiffLineContext || l.OldLineNo != 11 || l.NewLineNo != 12 {
+		t.Errorf("Unexpected context line: %+v", l)
+	}
+	if *modified.Additions != 3 || *modified.Deletions != 2 || *modified.Changes != 5 {
+		t.Errorf("Unexpected stats: +%d -%d", *modified.Additions, *modified.Deletions)
+	}
+
+	added := files[1]
+	if *added.Path != "NEW.md" || *added.Status != FileDiffStatusAdded {
+		t.Errorf("Unexpected added file: path %q, status %q", *added.Path, *added.Status)
+	}
+	if len(added.Hunks) != 1 || len(added.Hunks[0].Lines) != 1 || added.Hunks[0].Lines[0].Content != "-- not a file header" {
+		t.Errorf("Unexpected hunks for added file: %+v", added.Hunks)
+	}
+
+	renamed := files[2]
+	if *renamed.Status != FileDiffStatusRenamed || *renamed.OldPath != "old.txt" || *renamed.Path != "renamed.txt" {
+		t.Errorf("Unexpected renamed file: %q -> %q (%q)", *renamed.OldPath, *renamed.Path, *renamed.Status)
+	}
+
+	binary := files[3]
+	if binary.IsBinary == nil || !*binary.IsBinary || len(binary.Hunks) != 0 {
+		t.Errorf("Expected binary file without hunks, got %+v", binary)
+	}
+}
+
+// TestParseUnifiedDiffErrors tests that malformed input is reported
+func TestParseUnifiedDiffErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"malformed header", "--- a/x\n+++ b/x\n@@ -1 +x @@\n", "line 3: malformed hunk header"},
+		{"hunk without file", "@@ -1 +1 @@\n", "hunk before file header"},
+		{"truncated hunk", "--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n a\n", "unexpected end of input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseUnifiedDiff(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
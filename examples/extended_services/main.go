@@ -43,7 +43,7 @@ func main() {
 			Page:  gitness.Ptr(1),
 			Limit: gitness.Ptr(10),
 		},
-		Type: gitness.Ptr("user"),
+		Type: gitness.Ptr(gitness.PrincipalTypeUser),
 	})
 	if err != nil {
 		fmt.Printf("Error listing principals: %v\n", err)
@@ -131,7 +131,7 @@ func main() {
 				Page:  gitness.Ptr(1),
 				Limit: gitness.Ptr(10),
 			},
-			Status: gitness.Ptr("success"),
+			Status: gitness.Ptr(gitness.ExecutionStatusSuccess),
 		})
 	if err != nil {
 		fmt.Printf("Error listing pipeline executions: %v\n", err)
@@ -185,8 +185,10 @@ func main() {
 			APIURL:   gitness.Ptr("https://api.github.com"),
 			Insecure: gitness.Ptr(false),
 			Auth: &gitness.ConnectorAuth{
-				AuthType: gitness.ConnectorAuthTypeBearer,
-				Token:    gitness.Ptr("github_pat_xxxx"),
+				Type: gitness.Ptr(gitness.ConnectorAuthTypeBearer),
+				Bearer: &gitness.BearerTokenCredentials{
+					Token: &gitness.SecretReference{Identifier: gitness.Ptr("github-token")},
+				},
 			},
 		},
 	}
@@ -195,9 +197,11 @@ func main() {
 		fmt.Printf("Error creating GitHub connector: %v\n", err)
 	}
 
-	connectors, _, err := client.Connectors.ListConnectors(ctx, &gitness.ListOptions{
-		Page:  gitness.Ptr(1),
-		Limit: gitness.Ptr(10),
+	connectors, _, err := client.Connectors.ListConnectors(ctx, &gitness.ListConnectorsOptions{
+		ListOptions: gitness.ListOptions{
+			Page:  gitness.Ptr(1),
+			Limit: gitness.Ptr(10),
+		},
 	})
 	if err != nil {
 		fmt.Printf("Error listing connectors: %v\n", err)
@@ -8,11 +8,21 @@ package gitness
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	mrand "math/rand/v2"
+	"mime"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/imroc/req/v3"
@@ -22,6 +32,9 @@ const (
 	defaultBaseURL = "https://gitness.com/"
 	apiVersionPath = "api/v1"
 	userAgent      = "go-gitness"
+
+	// maxBodySnippet limits how much of an undecodable body is kept in a DecodeError
+	maxBodySnippet = 256
 )
 
 // Client represents a Gitness API client
@@ -30,6 +43,45 @@ type Client struct {
 	baseURL string
 	token   string
 
+	// doer builds and sends requests; it wraps client unless replaced in tests
+	doer httpDoer
+
+	// retryPolicy decides whether a failed request is retried
+	retryPolicy RetryPolicy
+
+	// retryOnConnectionError allows retrying DNS, dial and connection reset errors
+	retryOnConnectionError bool
+
+	// Exponential backoff between retries; retryJitter picks the actual delay
+	// in [0, ceiling]
+	retryBackoffBase time.Duration
+	retryBackoffMax  time.Duration
+	retryJitter      func(ceiling time.Duration) time.Duration
+
+	// clock provides the current time and poll waits
+	clock Clock
+
+	// autoIdempotencyKeys adds a generated Idempotency-Key to every POST
+	autoIdempotencyKeys bool
+
+	// defaultListLimit is sent as the limit of list requests that do not set
+	// one; 0 leaves the server default
+	defaultListLimit int
+
+	// pageSizeParams overrides the page size parameter name per endpoint
+	pageSizeParams []pageSizeOverride
+
+	// maxUploadSize is the largest file CreateUpload accepts; 0 disables the check
+	maxUploadSize int64
+
+	// features maps each registered Feature to its first server release
+	features map[Feature]serverVersion
+
+	// Cached server version used by Supports, and the fetch in flight
+	versionMu   sync.Mutex
+	version     *serverVersion
+	versionCall *versionCall
+
 	// Services
 	Admin          *AdminService
 	Audit          *AuditService
@@ -66,13 +118,25 @@ func NewClient(token string, options ...ClientOptionFunc) (*Client, error) {
 		SetUserAgent(userAgent).
 		SetTimeout(10 * time.Second).
 		SetCommonBearerAuthToken(token).
-		SetCommonContentType("application/json")
+		SetCommonContentType("application/json").
+		OnBeforeRequest(setOperationUserAgent).
+		OnAfterResponse(wrapDecodeError)
 
 	c := &Client{
-		client:  reqClient,
-		baseURL: baseURL,
-		token:   token,
+		client:                 reqClient,
+		baseURL:                baseURL,
+		token:                  token,
+		retryPolicy:            DefaultRetryPolicy,
+		retryOnConnectionError: true,
+		retryBackoffBase:       DefaultRetryBackoffBase,
+		retryBackoffMax:        DefaultRetryBackoffMax,
+		retryJitter:            fullJitter,
+		clock:                  systemClock{},
 	}
+	reqClient.SetCommonRetryCondition(c.shouldRetry).
+		SetCommonRetryInterval(c.retryInterval).
+		OnBeforeRequest(c.setIdempotencyKey).
+		OnBeforeRequest(c.setPageSizeParam)
 
 	// Apply options
 	for _, option := range options {
@@ -84,6 +148,9 @@ func NewClient(token string, options ...ClientOptionFunc) (*Client, error) {
 	// Set the base URL with API version
 	apiURL := c.baseURL + apiVersionPath
 	c.client.SetBaseURL(apiURL)
+	if c.doer == nil {
+		c.doer = reqDoer{client: c.client}
+	}
 
 	// Initialize services
 	c.Admin = &AdminService{client: c}
@@ -114,15 +181,37 @@ func NewClient(token string, options ...ClientOptionFunc) (*Client, error) {
 // WithBaseURL sets a custom base URL for the client
 func WithBaseURL(baseURL string) ClientOptionFunc {
 	return func(c *Client) error {
-		u, err := url.Parse(baseURL)
+		normalized, err := normalizeBaseURL(baseURL)
 		if err != nil {
 			return err
 		}
-		c.baseURL = u.String()
+		c.baseURL = normalized
 		return nil
 	}
 }
 
+// normalizeBaseURL validates baseURL and returns it with exactly one trailing
+// slash and without a trailing api/v1 segment, so that pasting either the
+// server root or the full API URL yields the same client configuration.
+func normalizeBaseURL(baseURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	path = strings.TrimSuffix(path, "/"+apiVersionPath)
+	u.Path = strings.TrimRight(path, "/") + "/"
+	u.RawPath = ""
+	return u.String(), nil
+}
+
 // WithHTTPClient sets a custom HTTP client
 func WithHTTPClient(httpClient *http.Client) ClientOptionFunc {
 	return func(c *Client) error {
@@ -148,7 +237,9 @@ func WithDebug() ClientOptionFunc {
 	}
 }
 
-// WithRetry enables retry mechanism with default configuration
+// WithRetry enables retry mechanism with default configuration.
+// Failed requests are retried according to the client's RetryPolicy, waiting
+// with jittered exponential backoff between attempts (see WithRetryBackoff).
 func WithRetry(retryCount int) ClientOptionFunc {
 	return func(c *Client) error {
 		if retryCount > 0 {
@@ -158,6 +249,203 @@ func WithRetry(retryCount int) ClientOptionFunc {
 	}
 }
 
+// Default bounds of the exponential retry backoff
+const (
+	DefaultRetryBackoffBase = 100 * time.Millisecond
+	DefaultRetryBackoffMax  = 5 * time.Second
+)
+
+// WithRetryBackoff sets the exponential backoff used between retries. The
+// n-th retry waits a random duration between 0 and min(max, base*2^(n-1))
+// ("full jitter"), so clients recovering from the same outage spread out.
+func WithRetryBackoff(base, max time.Duration) ClientOptionFunc {
+	return func(c *Client) error {
+		if base <= 0 || max < base {
+			return fmt.Errorf("invalid retry backoff: base %v, max %v", base, max)
+		}
+		c.retryBackoffBase = base
+		c.retryBackoffMax = max
+		return nil
+	}
+}
+
+// retryBackoffCeiling returns the upper bound of the delay before retry attempt (starting at 1)
+func (c *Client) retryBackoffCeiling(attempt int) time.Duration {
+	ceiling := c.retryBackoffBase
+	for i := 1; i < attempt && ceiling < c.retryBackoffMax; i++ {
+		ceiling *= 2
+	}
+	return min(ceiling, c.retryBackoffMax)
+}
+
+// retryInterval is the req/v3 retry interval function
+func (c *Client) retryInterval(_ *req.Response, attempt int) time.Duration {
+	return c.retryJitter(c.retryBackoffCeiling(attempt))
+}
+
+// fullJitter returns a random duration in [0, ceiling]
+func fullJitter(ceiling time.Duration) time.Duration {
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(mrand.Int64N(int64(ceiling) + 1))
+}
+
+// RetryPolicy reports whether a failed request should be retried.
+// resp is never nil but resp.Response may be nil when err is a network error.
+type RetryPolicy func(resp *Response, err error) bool
+
+// WithRetryPolicy overrides DefaultRetryPolicy. It only takes effect together with WithRetry.
+func WithRetryPolicy(policy RetryPolicy) ClientOptionFunc {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("retry policy must not be nil")
+		}
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithDefaultListLimit sets the page size sent by list calls whose options
+// leave Limit nil. Calls that set Limit explicitly are unaffected.
+func WithDefaultListLimit(limit int) ClientOptionFunc {
+	return func(c *Client) error {
+		if limit <= 0 {
+			return fmt.Errorf("default list limit must be positive, got %d", limit)
+		}
+		c.defaultListLimit = limit
+		return nil
+	}
+}
+
+// WithRetryOnConnectionError controls whether network errors such as DNS
+// failures and connection resets are retried. It is enabled by default and,
+// like status retries, only takes effect together with WithRetry. Disabling
+// it leaves HTTP status retries unchanged.
+func WithRetryOnConnectionError(enabled bool) ClientOptionFunc {
+	return func(c *Client) error {
+		c.retryOnConnectionError = enabled
+		return nil
+	}
+}
+
+// IsConnectionError reports whether err is a transport-level failure, such
+// as a DNS lookup error, refused or reset connection, or a connection closed
+// before the response was read, rather than an error response from the server
+func IsConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	if errors.As(err, &dnsErr) || errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+type allowRetryKey struct{}
+
+// AllowRetry marks requests made with ctx as safe to retry under
+// DefaultRetryPolicy even when they use a non-idempotent method
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
+
+// retryAllowed reports whether ctx was marked with AllowRetry
+func retryAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(allowRetryKey{}).(bool)
+	return allowed
+}
+
+// isIdempotentMethod reports whether repeating a request with method has no additional effect
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultRetryPolicy retries connection errors (see IsConnectionError), 429
+// and 5xx responses for idempotent methods. POST and PATCH requests are only
+// retried when their context was marked with AllowRetry, since the server may
+// already have applied them before failing.
+func DefaultRetryPolicy(resp *Response, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if resp.Response == nil {
+		return IsConnectionError(err)
+	}
+	if r := resp.Request; r != nil && !isIdempotentMethod(r.Method) &&
+		!retryAllowed(r.Context()) {
+		return false
+	}
+	if resp.Response.Response == nil {
+		return IsConnectionError(err)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey attaches an Idempotency-Key to POST requests made with
+// ctx. An empty key generates a random one. The same key is sent on every
+// retry. The header is advisory: Gitness does not document deduplicating on
+// it, so it does not make a POST retryable; use AllowRetry for that.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		key = newIdempotencyKey()
+	}
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// WithAutoIdempotencyKeys generates an Idempotency-Key for every POST request
+// that does not already carry one
+func WithAutoIdempotencyKeys() ClientOptionFunc {
+	return func(c *Client) error {
+		c.autoIdempotencyKeys = true
+		return nil
+	}
+}
+
+// newIdempotencyKey returns a random 128-bit hex key
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// setIdempotencyKey sets the Idempotency-Key header on POST requests. The
+// header is kept on the request, so retries reuse the first key.
+func (c *Client) setIdempotencyKey(_ *req.Client, r *req.Request) error {
+	if r.Method != http.MethodPost || r.Headers.Get(idempotencyKeyHeader) != "" {
+		return nil
+	}
+	if key, ok := r.Context().Value(idempotencyKeyKey{}).(string); ok {
+		r.SetHeader(idempotencyKeyHeader, key)
+	} else if c.autoIdempotencyKeys {
+		r.SetHeader(idempotencyKeyHeader, newIdempotencyKey())
+	}
+	return nil
+}
+
+// shouldRetry adapts the client's RetryPolicy to a req/v3 retry condition.
+// Connection errors are never retried when WithRetryOnConnectionError(false) is set.
+func (c *Client) shouldRetry(resp *req.Response, err error) bool {
+	if !c.retryOnConnectionError && IsConnectionError(err) {
+		return false
+	}
+	return c.retryPolicy(&Response{Response: resp}, err)
+}
+
 // Response wraps an HTTP response from req/v3 with pagination information
 type Response struct {
 	*req.Response
@@ -168,6 +456,59 @@ type Response struct {
 	NextPage   *int `json:"next_page,omitempty"`
 	Total      *int `json:"total,omitempty"`
 	TotalPages *int `json:"total_pages,omitempty"`
+
+	// Truncated is set by the ListAll helpers when ListAllOptions.MaxResults
+	// stopped pagination before every item was fetched
+	Truncated bool
+}
+
+// NotModified reports whether the server answered a conditional request with 304 Not Modified
+func (r *Response) NotModified() bool {
+	return r != nil && r.Response != nil && r.Response.Response != nil &&
+		r.StatusCode == http.StatusNotModified
+}
+
+// httpResponse returns the underlying HTTP response, nil when there is none
+func (r *Response) httpResponse() *http.Response {
+	if r == nil || r.Response == nil {
+		return nil
+	}
+	return r.Response.Response
+}
+
+// Header returns the first value of the response header key, or "" when it
+// is not set or there was no response
+func (r *Response) Header(key string) string {
+	if resp := r.httpResponse(); resp != nil {
+		return resp.Header.Get(key)
+	}
+	return ""
+}
+
+// Trailer returns the first value of the response trailer key. Trailers are
+// only available once the body was read completely.
+func (r *Response) Trailer(key string) string {
+	if resp := r.httpResponse(); resp != nil {
+		return resp.Trailer.Get(key)
+	}
+	return ""
+}
+
+// RequestID returns the X-Request-Id Gitness assigns to each request, useful
+// for correlating a call with server logs
+func (r *Response) RequestID() string {
+	return r.Header("X-Request-Id")
+}
+
+// ETag returns the entity tag of the response
+func (r *Response) ETag() string {
+	return r.Header("ETag")
+}
+
+// LastModified returns the Last-Modified time of the response and whether it was set
+func (r *Response) LastModified() (time.Time, bool) {
+	t, err := http.ParseTime(r.Header("Last-Modified"))
+	return t, err == nil
 }
 
 // ErrorResponse represents an error response from the API
@@ -186,12 +527,135 @@ func (e *ErrorResponse) Error() string {
 	return e.Message
 }
 
+// ErrUnauthorized matches API errors caused by a missing, invalid or expired token
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrNotFound matches API errors for resources that do not exist
+var ErrNotFound = errors.New("not found")
+
+// unauthorizedMessage replaces the server's generic 401 message
+const unauthorizedMessage = "authentication failed: invalid or expired token"
+
+// IsUnauthorized reports whether the API rejected the request's credentials
+func (e *ErrorResponse) IsUnauthorized() bool {
+	return e.Response != nil && e.Response.StatusCode == http.StatusUnauthorized
+}
+
+// IsNotFound reports whether the requested resource does not exist
+func (e *ErrorResponse) IsNotFound() bool {
+	return e.Response != nil && e.Response.StatusCode == http.StatusNotFound
+}
+
+// Unwrap returns ErrUnauthorized for 401 responses and ErrNotFound for 404
+// responses so callers can use errors.Is
+func (e *ErrorResponse) Unwrap() error {
+	switch {
+	case e.IsUnauthorized():
+		return ErrUnauthorized
+	case e.IsNotFound():
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ErrUnexpectedContentType is wrapped by a DecodeError when a response that
+// should carry JSON is served with a different content type
+var ErrUnexpectedContentType = errors.New("unexpected content type, expected JSON")
+
+// DecodeError is returned when a successful response body cannot be decoded,
+// e.g. when a reverse proxy answers with an HTML page instead of JSON
+type DecodeError struct {
+	Response    *req.Response `json:"-"`
+	ContentType string        `json:"content_type,omitempty"`
+	Snippet     string        `json:"snippet,omitempty"`
+	Err         error         `json:"-"`
+}
+
+func (e *DecodeError) Error() string {
+	if e.Response != nil && e.Response.Request != nil {
+		return fmt.Sprintf("%v %v: %d: cannot decode response (got %s): %v: %q",
+			e.Response.Request.Method, e.Response.Request.URL,
+			e.Response.StatusCode, e.ContentType, e.Err, e.Snippet)
+	}
+	return fmt.Sprintf("cannot decode response (got %s): %v: %q", e.ContentType, e.Err, e.Snippet)
+}
+
+// Unwrap returns the underlying decode error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// PartialUpdateError is returned by updates that are applied through several
+// requests when one of them fails after others have succeeded. The changes
+// listed in Applied are in effect on the server.
+type PartialUpdateError struct {
+	Applied []string
+	Failed  string
+	Err     error
+}
+
+func (e *PartialUpdateError) Error() string {
+	return fmt.Sprintf("update partially applied (applied %s, failed %s): %v",
+		strings.Join(e.Applied, ", "), e.Failed, e.Err)
+}
+
+func (e *PartialUpdateError) Unwrap() error {
+	return e.Err
+}
+
+// partialUpdate wraps err in a PartialUpdateError when earlier parts of the
+// update were applied, and returns it unchanged otherwise
+func partialUpdate(applied []string, failed string, err error) error {
+	if len(applied) == 0 {
+		return err
+	}
+	return &PartialUpdateError{Applied: applied, Failed: failed, Err: err}
+}
+
+// newDecodeError builds a DecodeError with a truncated snippet of the response body
+func newDecodeError(r *req.Response, err error) *DecodeError {
+	snippet := r.String()
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet] + "..."
+	}
+
+	contentType := r.GetContentType()
+	if contentType == "" {
+		contentType = "no content type"
+	}
+
+	return &DecodeError{
+		Response:    r,
+		ContentType: contentType,
+		Snippet:     snippet,
+		Err:         err,
+	}
+}
+
+// wrapDecodeError is a response middleware that turns failures to decode a
+// successful response into a DecodeError carrying the status and body snippet
+func wrapDecodeError(_ *req.Client, r *req.Response) error {
+	if r.Err == nil || r.Response == nil || !r.IsSuccessState() {
+		return nil
+	}
+
+	// Cancellation while reading the body is not a decode failure
+	if errors.Is(r.Err, context.Canceled) || errors.Is(r.Err, context.DeadlineExceeded) {
+		return nil
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(r.Err, &decodeErr) {
+		return nil
+	}
+
+	return newDecodeError(r, r.Err)
+}
+
 // Get performs a GET request
 func (c *Client) Get(ctx context.Context, path string, result any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	resp, err := c.client.R().
-		SetContext(ctx).
-		SetSuccessResult(result).
+	resp, err := c.newRequest(ctx).
 		Get(fullURL)
 
 	if err != nil {
@@ -202,17 +666,18 @@ func (c *Client) Get(ctx context.Context, path string, result any) (*Response, e
 		return &Response{Response: resp}, err
 	}
 
-	// Parse pagination headers
-	response := &Response{Response: resp}
-	c.parsePaginationHeaders(response)
+	if err := c.decodeResponse(resp, result); err != nil {
+		return &Response{Response: resp}, err
+	}
 
-	return response, nil
+	// Pagination headers are only parsed for list endpoints, see performListRequest
+	return &Response{Response: resp}, nil
 }
 
 // Post performs a POST request
 func (c *Client) Post(ctx context.Context, path string, body any, result any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	req := c.client.R().SetContext(ctx)
+	req := c.newRequest(ctx)
 
 	if body != nil {
 		req.SetBodyJsonMarshal(body)
@@ -237,7 +702,7 @@ func (c *Client) Post(ctx context.Context, path string, body any, result any) (*
 // Put performs a PUT request
 func (c *Client) Put(ctx context.Context, path string, body any, result any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	req := c.client.R().SetContext(ctx)
+	req := c.newRequest(ctx)
 
 	if body != nil {
 		req.SetBodyJsonMarshal(body)
@@ -262,7 +727,7 @@ func (c *Client) Put(ctx context.Context, path string, body any, result any) (*R
 // Patch performs a PATCH request
 func (c *Client) Patch(ctx context.Context, path string, body any, result any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	req := c.client.R().SetContext(ctx)
+	req := c.newRequest(ctx)
 
 	if body != nil {
 		req.SetBodyJsonMarshal(body)
@@ -287,7 +752,7 @@ func (c *Client) Patch(ctx context.Context, path string, body any, result any) (
 // Delete performs a DELETE request
 func (c *Client) Delete(ctx context.Context, path string, body any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	req := c.client.R().SetContext(ctx)
+	req := c.newRequest(ctx)
 
 	if body != nil {
 		req.SetBodyJsonMarshal(body)
@@ -308,7 +773,7 @@ func (c *Client) Delete(ctx context.Context, path string, body any) (*Response,
 // DeleteWithResponse performs a DELETE request and returns the response body
 func (c *Client) DeleteWithResponse(ctx context.Context, path string, body any, result any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	req := c.client.R().SetContext(ctx)
+	req := c.newRequest(ctx)
 
 	if body != nil {
 		req.SetBodyJsonMarshal(body)
@@ -349,6 +814,10 @@ func (c *Client) checkResponse(r *req.Response) error {
 		}
 	}
 
+	if errorResponse.IsUnauthorized() &&
+		(errorResponse.Message == "" || strings.EqualFold(errorResponse.Message, http.StatusText(http.StatusUnauthorized))) {
+		errorResponse.Message = unauthorizedMessage
+	}
 	if errorResponse.Message == "" {
 		errorResponse.Message = fmt.Sprintf("HTTP %d: %s", r.StatusCode, http.StatusText(r.StatusCode))
 	}
@@ -356,17 +825,59 @@ func (c *Client) checkResponse(r *req.Response) error {
 	return errorResponse
 }
 
-// buildFullURL constructs a full URL from the base URL and path, preserving URL encoding
+// isNotFound reports whether err is an API error with a 404 status
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// notFoundAsNil turns a 404 error from a lookup into a nil result without an error
+func notFoundAsNil[T any](v *T, resp *Response, err error) (*T, *Response, error) {
+	if isNotFound(err) {
+		return nil, resp, nil
+	}
+	return v, resp, err
+}
+
+// decodeResponse verifies that the response carries JSON before decoding it into result.
+// Streaming endpoints read the raw body and must not go through this check.
+func (c *Client) decodeResponse(r *req.Response, result any) error {
+	if result == nil || r.StatusCode == http.StatusNoContent || len(r.Bytes()) == 0 {
+		return nil
+	}
+
+	if !isJSONContentType(r.GetContentType()) {
+		return newDecodeError(r, ErrUnexpectedContentType)
+	}
+
+	if err := json.Unmarshal(r.Bytes(), result); err != nil {
+		return newDecodeError(r, err)
+	}
+
+	return nil
+}
+
+// isJSONContentType reports whether the content type denotes a JSON document
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// buildFullURL constructs a full URL from the base URL and an already escaped
+// path. It joins the two the same way req resolves relative request paths
+// against the client base URL, so escaped segments such as %2F are sent once
+// and never re-encoded.
 func (c *Client) buildFullURL(path string) string {
-	baseURL, _ := url.Parse(c.baseURL + apiVersionPath + "/")
-	fullURL := baseURL.ResolveReference(&url.URL{Path: path})
-	return fullURL.String()
+	return c.baseURL + apiVersionPath + "/" + strings.TrimLeft(path, "/")
 }
 
-// buildQueryParams is a helper function to build query parameters from ListOptions
-func buildQueryParams(req *req.Request, opt *ListOptions) {
+// buildQueryParams is a helper function to build query parameters from ListOptions.
+// A nil opt or Limit falls back to the client's default list limit, if any.
+func (c *Client) buildQueryParams(req httpRequest, opt *ListOptions) {
 	if opt == nil {
-		return
+		opt = &ListOptions{}
 	}
 
 	if opt.Page != nil {
@@ -374,6 +885,8 @@ func buildQueryParams(req *req.Request, opt *ListOptions) {
 	}
 	if opt.Limit != nil {
 		req.SetQueryParam("limit", fmt.Sprintf("%d", *opt.Limit))
+	} else if c.defaultListLimit > 0 {
+		req.SetQueryParam("limit", fmt.Sprintf("%d", c.defaultListLimit))
 	}
 	if opt.Sort != nil {
 		req.SetQueryParam("sort", *opt.Sort)
@@ -389,11 +902,10 @@ func buildQueryParams(req *req.Request, opt *ListOptions) {
 // performListRequest is a helper function for making list requests with pagination support
 func (c *Client) performListRequest(ctx context.Context, path string, opt *ListOptions, result any) (*Response, error) {
 	fullURL := c.buildFullURL(path)
-	req := c.client.R().SetContext(ctx)
-	req.SetSuccessResult(result)
+	req := c.newRequest(ctx)
 
 	// Add common query parameters
-	buildQueryParams(req, opt)
+	c.buildQueryParams(req, opt)
 
 	resp, err := req.Get(fullURL)
 	if err != nil {
@@ -404,6 +916,10 @@ func (c *Client) performListRequest(ctx context.Context, path string, opt *ListO
 		return &Response{Response: resp}, err
 	}
 
+	if err := c.decodeResponse(resp, result); err != nil {
+		return &Response{Response: resp}, err
+	}
+
 	// Parse pagination headers
 	response := &Response{Response: resp}
 	c.parsePaginationHeaders(response)
@@ -411,6 +927,49 @@ func (c *Client) performListRequest(ctx context.Context, path string, opt *ListO
 	return response, nil
 }
 
+// performListRequestWithParams performs a list GET whose query is built by
+// setParams, decoding into result and parsing pagination headers
+func (c *Client) performListRequestWithParams(ctx context.Context, path string, setParams func(httpRequest), result any) (*Response, error) {
+	return c.doListRequest(ctx, path, setParams, result, false)
+}
+
+// performConditionalListRequest is performListRequestWithParams for list
+// requests that setParams makes conditional. A 304 Not Modified answer is
+// returned without error and leaves result untouched.
+func (c *Client) performConditionalListRequest(ctx context.Context, path string, setParams func(httpRequest), result any) (*Response, error) {
+	return c.doListRequest(ctx, path, setParams, result, true)
+}
+
+// doListRequest performs a list GET, passing a 304 through when notModifiedOK is set
+func (c *Client) doListRequest(ctx context.Context, path string, setParams func(httpRequest), result any, notModifiedOK bool) (*Response, error) {
+	req := c.newRequest(ctx)
+	if setParams != nil {
+		setParams(req)
+	}
+
+	resp, err := req.Get(c.buildFullURL(path))
+	if err != nil {
+		return &Response{Response: resp}, err
+	}
+
+	if notModifiedOK && resp.StatusCode == http.StatusNotModified {
+		return &Response{Response: resp}, nil
+	}
+
+	if err := c.checkResponse(resp); err != nil {
+		return &Response{Response: resp}, err
+	}
+
+	if err := c.decodeResponse(resp, result); err != nil {
+		return &Response{Response: resp}, err
+	}
+
+	response := &Response{Response: resp}
+	c.parsePaginationHeaders(response)
+
+	return response, nil
+}
+
 // parsePaginationHeaders parses pagination information from response headers
 func (c *Client) parsePaginationHeaders(response *Response) {
 	if response.Response == nil {
@@ -489,11 +1048,13 @@ func (t Time) String() string {
 	return time.Time(t).Format(time.RFC3339)
 }
 
-// ListOptions specifies general pagination options
+// ListOptions specifies general pagination options. They are only ever sent
+// as query parameters; the fields are excluded from JSON so that embedding
+// ListOptions never leaks pagination into a request body.
 type ListOptions struct {
-	Page  *int    `json:"page,omitempty" url:"page,omitempty"`
-	Limit *int    `json:"limit,omitempty" url:"limit,omitempty"` // Gitness uses 'limit' not 'per_page'
-	Sort  *string `json:"sort,omitempty" url:"sort,omitempty"`
-	Order *string `json:"order,omitempty" url:"order,omitempty"`
-	Query *string `json:"query,omitempty" url:"query,omitempty"`
+	Page  *int    `json:"-" url:"page,omitempty"`
+	Limit *int    `json:"-" url:"limit,omitempty"` // Gitness uses 'limit' not 'per_page', see WithPageSizeParam
+	Sort  *string `json:"-" url:"sort,omitempty"`
+	Order *string `json:"-" url:"order,omitempty"`
+	Query *string `json:"-" url:"query,omitempty"`
 }
@@ -7,14 +7,17 @@
 package gitness
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
-
-	"github.com/imroc/req/v3"
 )
 
 func TestNewClientWithReqV3(t *testing.T) {
@@ -65,6 +68,41 @@ func TestNewClientWithOptions(t *testing.T) {
 	}
 }
 
+func TestWithBaseURLNormalization(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "https://git.example.com", want: "https://git.example.com/"},
+		{input: "https://git.example.com/", want: "https://git.example.com/"},
+		{input: "https://git.example.com/api/v1", want: "https://git.example.com/"},
+		{input: "https://git.example.com/api/v1/", want: "https://git.example.com/"},
+		{input: "https://example.com/gitness//", want: "https://example.com/gitness/"},
+		{input: "https://example.com/gitness/api/v1", want: "https://example.com/gitness/"},
+		{input: "git.example.com", wantErr: true},
+		{input: "ftp://git.example.com/", wantErr: true},
+		{input: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		client, err := NewClient("test-token", WithBaseURL(tt.input))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("WithBaseURL(%q) expected error, got baseURL %q", tt.input, client.baseURL)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("WithBaseURL(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if client.baseURL != tt.want {
+			t.Errorf("WithBaseURL(%q) = %q, want %q", tt.input, client.baseURL, tt.want)
+		}
+	}
+}
+
 func TestClientHTTPMethods(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -182,6 +220,131 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestUnauthorizedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("expired-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	_, _, err = client.Users.GetCurrentUser(context.Background())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected ErrUnauthorized, got %v", err)
+	}
+
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || !errorResponse.IsUnauthorized() {
+		t.Fatalf("Expected unauthorized ErrorResponse, got %T", err)
+	}
+	if errorResponse.Message != unauthorizedMessage {
+		t.Errorf("Expected message %q, got %q", unauthorizedMessage, errorResponse.Message)
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"identifier":"demo","path":"space/demo"}`))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	repo, _, err := client.Repositories.GetRepository(context.Background(), "space/demo")
+	if err != nil {
+		t.Fatalf("GetRepository returned error: %v", err)
+	}
+	if repo.Identifier == nil || *repo.Identifier != "demo" {
+		t.Errorf("Expected identifier demo, got %v", repo.Identifier)
+	}
+}
+
+func TestDecodeErrorIncludesBodySnippet(t *testing.T) {
+	// Simulate a reverse proxy answering with an HTML page on a success status
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	var result map[string]string
+	_, err = client.Get(context.Background(), "test", &result)
+	if err == nil {
+		t.Fatal("Expected decode error, got nil")
+	}
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected DecodeError, got %T: %v", err, err)
+	}
+
+	if decodeErr.ContentType != "text/html" {
+		t.Errorf("Expected content type %q, got %q", "text/html", decodeErr.ContentType)
+	}
+
+	if decodeErr.Snippet != "<html><body>502 Bad Gateway</body></html>" {
+		t.Errorf("Unexpected body snippet %q", decodeErr.Snippet)
+	}
+
+	if decodeErr.Unwrap() == nil {
+		t.Error("Expected DecodeError to wrap the underlying error")
+	}
+}
+
+func TestUnexpectedContentType(t *testing.T) {
+	// JSON-looking body served with a non-JSON content type must not be decoded silently
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	_, _, err = client.Repositories.ListBranches(context.Background(), "test/repo", nil)
+	if !errors.Is(err, ErrUnexpectedContentType) {
+		t.Fatalf("Expected ErrUnexpectedContentType, got %v", err)
+	}
+
+	// Lists whose query is built per method decode the same way
+	if _, _, err := client.Repositories.ListTags(context.Background(), "test/repo", nil); !errors.Is(err, ErrUnexpectedContentType) {
+		t.Errorf("Expected ErrUnexpectedContentType from ListTags, got %v", err)
+	}
+	if _, _, err := client.Repositories.ListCommits(context.Background(), "test/repo", nil); !errors.Is(err, ErrUnexpectedContentType) {
+		t.Errorf("Expected ErrUnexpectedContentType from ListCommits, got %v", err)
+	}
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.ContentType != "text/plain" {
+		t.Errorf("Expected DecodeError with content type text/plain, got %v", err)
+	}
+}
+
 func TestPullRequestOperationsWithReqV3(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -282,6 +445,7 @@ func TestRetryMechanism(t *testing.T) {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]string{"result": "success"})
 	}))
@@ -295,11 +459,6 @@ func TestRetryMechanism(t *testing.T) {
 		t.Fatalf("NewClient returned error: %v", err)
 	}
 
-	// Configure retry conditions (using correct req/v3 method)
-	client.client.SetCommonRetryCondition(func(resp *req.Response, err error) bool {
-		return resp.StatusCode >= 500
-	})
-
 	ctx := context.Background()
 	var result map[string]string
 	_, err = client.Get(ctx, "test", &result)
@@ -316,6 +475,318 @@ func TestRetryMechanism(t *testing.T) {
 	}
 }
 
+func TestRetrySkipsPost(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithRetry(3),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	_, err = client.Post(context.Background(), "test", map[string]string{"name": "repo"}, nil)
+	if err == nil {
+		t.Fatal("Expected error for 502 response")
+	}
+
+	if attemptCount != 1 {
+		t.Errorf("Expected POST to be attempted once, got %d", attemptCount)
+	}
+}
+
+func TestRetryAllowedPerCall(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		if attemptCount < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithRetry(3),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := AllowRetry(context.Background())
+	if _, err := client.Patch(ctx, "test", map[string]string{"name": "repo"}, nil); err != nil {
+		t.Fatalf("PATCH with AllowRetry failed: %v", err)
+	}
+
+	if attemptCount != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attemptCount)
+	}
+}
+
+func TestIdempotencyKeyReusedAcrossRetries(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithRetry(3),
+		WithAutoIdempotencyKeys(),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, err := client.Post(context.Background(), "test", nil, nil); err == nil {
+		t.Fatal("Expected POST with only an idempotency key not to be retried")
+	}
+	if len(keys) != 1 || keys[0] == "" {
+		t.Fatalf("Expected 1 attempt with a key, got %q", keys)
+	}
+
+	keys = nil
+	if _, err := client.Post(AllowRetry(context.Background()), "test", map[string]string{"name": "repo"}, nil); err != nil {
+		t.Fatalf("POST with idempotency key failed: %v", err)
+	}
+
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected the same non-empty key on each attempt, got %q", keys)
+	}
+
+	keys = nil
+	ctx := WithIdempotencyKey(AllowRetry(context.Background()), "create-repo-1")
+	if _, err := client.Post(ctx, "test", nil, nil); err != nil {
+		t.Fatalf("POST with explicit idempotency key failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "create-repo-1" || keys[1] != "create-repo-1" {
+		t.Errorf("Expected explicit key on each attempt, got %q", keys)
+	}
+}
+
+func TestRetryPolicyOverride(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithRetry(2),
+		WithRetryPolicy(func(resp *Response, err error) bool {
+			return resp.Response != nil && resp.StatusCode == http.StatusConflict
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, err := client.Get(context.Background(), "test", nil); err == nil {
+		t.Fatal("Expected error for 409 response")
+	}
+
+	if attemptCount != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attemptCount)
+	}
+}
+
+// TestRetryOnConnectionError tests that dropped connections are retried unless disabled
+func TestRetryOnConnectionError(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      []ClientOptionFunc
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"default", nil, 2, false},
+		{"disabled", []ClientOptionFunc{WithRetryOnConnectionError(false)}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attemptCount atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attemptCount.Add(1) == 1 {
+					// Drop the connection without a response
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						t.Fatalf("Hijack failed: %v", err)
+					}
+					conn.Close()
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			options := append([]ClientOptionFunc{WithBaseURL(server.URL + "/"), WithRetry(2)}, tt.options...)
+			client, err := NewClient("test-token", options...)
+			if err != nil {
+				t.Fatalf("NewClient returned error: %v", err)
+			}
+
+			_, err = client.Get(context.Background(), "test", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !IsConnectionError(err) {
+				t.Errorf("Expected a connection error, got %v", err)
+			}
+			if got := int(attemptCount.Load()); got != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+// TestRetryBackoff tests that the delay between retries grows exponentially up to the cap
+func TestRetryBackoff(t *testing.T) {
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrivals = append(arrivals, time.Now())
+		if len(arrivals) < 4 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithRetry(3),
+		WithRetryBackoff(20*time.Millisecond, 60*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	// Always wait the full ceiling so intervals are deterministic
+	client.retryJitter = func(ceiling time.Duration) time.Duration { return ceiling }
+
+	want := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond}
+	for i, w := range want {
+		if got := client.retryInterval(nil, i+1); got != w {
+			t.Errorf("Attempt %d: expected interval %v, got %v", i+1, w, got)
+		}
+	}
+
+	if _, err := client.Get(context.Background(), "test", nil); err != nil {
+		t.Fatalf("Request with retry failed: %v", err)
+	}
+	if len(arrivals) != 4 {
+		t.Fatalf("Expected 4 attempts, got %d", len(arrivals))
+	}
+	for i, w := range want {
+		if gap := arrivals[i+1].Sub(arrivals[i]); gap < w {
+			t.Errorf("Retry %d: expected to wait at least %v, waited %v", i+1, w, gap)
+		}
+	}
+
+	for range 100 {
+		if d := fullJitter(time.Second); d < 0 || d > time.Second {
+			t.Fatalf("fullJitter returned %v outside [0, 1s]", d)
+		}
+	}
+
+	if _, err := NewClient("test-token", WithRetryBackoff(time.Second, time.Millisecond)); err == nil {
+		t.Error("Expected error when max is smaller than base")
+	}
+}
+
+func TestGetIgnoresPaginationHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-total", "42")
+		w.Header().Set("x-page", "1")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	resp, err := client.Get(context.Background(), "test", nil)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	if resp.Total != nil || resp.Page != nil {
+		t.Errorf("Expected no pagination fields on single-object response, got total=%v page=%v", resp.Total, resp.Page)
+	}
+}
+
+func TestUpdateOptionsSendZeroValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		opt      any
+		expected string
+	}{
+		{"repository", &UpdateRepositoryOptions{Description: Ptr("")}, `{"description":""}`},
+		{"space", &UpdateSpaceOptions{Description: Ptr(""), IsPublic: Ptr(false)}, `{"description":"","is_public":false}`},
+		{"pipeline", &UpdatePipelineOptions{Disabled: Ptr(false)}, `{"disabled":false}`},
+		{"pipeline trigger", &UpdatePipelineTriggerOptions{Disabled: Ptr(false)}, `{"disabled":false}`},
+		{"secret", &CreateSecretOptions{Description: Ptr("")}, `{"description":""}`},
+		{"unset", &UpdatePipelineOptions{}, `{}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.opt)
+		if err != nil {
+			t.Fatalf("%s: Marshal returned error: %v", tt.name, err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, data)
+		}
+	}
+}
+
+func TestListOptionsNotInJSONBody(t *testing.T) {
+	list := ListOptions{Page: Ptr(2), Limit: Ptr(50), Sort: Ptr("name"), Order: Ptr("asc"), Query: Ptr("x")}
+	tests := []struct {
+		name     string
+		opt      any
+		expected string
+	}{
+		{"list options", &list, `{}`},
+		{"create options reusing list options", &struct {
+			*CreateRepositoryOptions
+			ListOptions
+		}{&CreateRepositoryOptions{Identifier: Ptr("repo")}, list}, `{"identifier":"repo"}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.opt)
+		if err != nil {
+			t.Fatalf("%s: Marshal returned error: %v", tt.name, err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, data)
+		}
+	}
+}
+
 func TestPtr(t *testing.T) {
 	str := "test"
 	strPtr := Ptr(str)
@@ -424,6 +895,12 @@ func TestAllListMethodsPagination(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 
+		// The audit log endpoint reports pagination in its body
+		if r.URL.Path == "/api/v1/admin/audit" {
+			w.Write([]byte(`{"audit_logs": [], "page": 1, "size": 10, "total": 50}`))
+			return
+		}
+
 		// Return empty arrays for all list endpoints
 		w.Write([]byte("[]"))
 	}))
@@ -534,8 +1011,8 @@ func TestAllListMethodsPagination(t *testing.T) {
 		{
 			"Connectors.ListConnectors",
 			func() (*Response, error) {
-				_, resp, err := client.Connectors.ListConnectors(ctx, &ListOptions{
-					Page: Ptr(1), Limit: Ptr(10),
+				_, resp, err := client.Connectors.ListConnectors(ctx, &ListConnectorsOptions{
+					ListOptions: ListOptions{Page: Ptr(1), Limit: Ptr(10)},
 				})
 				return resp, err
 			},
@@ -564,3 +1041,109 @@ func TestAllListMethodsPagination(t *testing.T) {
 		})
 	}
 }
+
+func TestOperationNameIsLoggedAndSent(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var entries []RequestLog
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithRequestLogger(func(_ context.Context, entry RequestLog) {
+			entries = append(entries, entry)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := WithOperationName(context.Background(), "sync-repos")
+	if _, _, err := client.Repositories.GetRepository(ctx, "test/repo"); err != nil {
+		t.Fatalf("GetRepository returned error: %v", err)
+	}
+
+	if gotUserAgent != "go-gitness op/sync-repos" {
+		t.Errorf("Expected operation in User-Agent, got %q", gotUserAgent)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(entries))
+	}
+	if entries[0].Operation != "sync-repos" || entries[0].Method != http.MethodGet || entries[0].StatusCode != http.StatusOK {
+		t.Errorf("Unexpected log entry: %+v", entries[0])
+	}
+}
+
+// TestDefaultListLimit tests that the default limit fills in only when Limit is unset
+func TestDefaultListLimit(t *testing.T) {
+	var limits []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/spaces", func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithDefaultListLimit(100))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	client.Spaces.ListSpaces(ctx, nil)
+	client.Spaces.ListSpaces(ctx, &ListSpacesOptions{ListOptions: ListOptions{Page: Ptr(2)}})
+	client.Spaces.ListSpaces(ctx, &ListSpacesOptions{ListOptions: ListOptions{Limit: Ptr(5)}})
+
+	if want := []string{"100", "100", "5"}; !reflect.DeepEqual(limits, want) {
+		t.Errorf("Expected limits %v, got %v", want, limits)
+	}
+
+	if _, err := NewClient("test-token", WithDefaultListLimit(0)); err == nil {
+		t.Error("Expected error for a non-positive default limit")
+	}
+}
+
+// TestResponseHeaderAccessors tests the header helpers, including on a nil response
+func TestResponseHeaderAccessors(t *testing.T) {
+	modified := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Write([]byte(`{"uid": "alice"}`))
+		w.Header().Set("X-Checksum", "sum")
+	})
+
+	client := NewTestClient(mux)
+
+	_, resp, err := client.Users.GetCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentUser returned error: %v", err)
+	}
+	if resp.RequestID() != "req-123" || resp.ETag() != `"abc"` || resp.Header("Content-Type") != "application/json" {
+		t.Errorf("Unexpected headers: request id %q, etag %q", resp.RequestID(), resp.ETag())
+	}
+	if lm, ok := resp.LastModified(); !ok || !lm.Equal(modified) {
+		t.Errorf("Expected last modified %v, got %v", modified, lm)
+	}
+	if resp.Trailer("X-Checksum") != "sum" {
+		t.Errorf("Expected trailer, got %q", resp.Trailer("X-Checksum"))
+	}
+
+	var empty *Response
+	if empty.Header("X-Request-Id") != "" || empty.RequestID() != "" {
+		t.Error("Expected empty headers on a nil response")
+	}
+	if _, ok := empty.LastModified(); ok {
+		t.Error("Expected no last modified time on a nil response")
+	}
+}
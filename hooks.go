@@ -0,0 +1,238 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+type operationNameKey struct{}
+
+// WithOperationName tags all requests made with ctx with an operation name.
+// The name is appended to the User-Agent and reported to the request logger.
+func WithOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// OperationName returns the operation name attached to ctx, if any
+func OperationName(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	name, _ := ctx.Value(operationNameKey{}).(string)
+	return name
+}
+
+// setOperationUserAgent appends the operation name to the User-Agent header
+func setOperationUserAgent(_ *req.Client, r *req.Request) error {
+	if name := OperationName(r.Context()); name != "" {
+		r.SetHeader("User-Agent", userAgent+" op/"+name)
+	}
+	return nil
+}
+
+// RequestLog describes a single API request attempt
+type RequestLog struct {
+	Operation  string
+	Method     string
+	URL        string
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// RequestLogger is called after every request attempt, including retries
+type RequestLogger func(ctx context.Context, entry RequestLog)
+
+// WithRequestLogger registers a hook that receives a RequestLog for every request
+func WithRequestLogger(logger RequestLogger) ClientOptionFunc {
+	return func(c *Client) error {
+		if logger == nil {
+			return nil
+		}
+		c.client.OnAfterResponse(func(_ *req.Client, r *req.Response) error {
+			if r.Request == nil {
+				return nil
+			}
+			ctx := r.Request.Context()
+			entry := RequestLog{
+				Operation: OperationName(ctx),
+				Method:    r.Request.Method,
+				Err:       r.Err,
+			}
+			if r.Request.RawRequest != nil {
+				entry.URL = r.Request.RawRequest.URL.String()
+			} else {
+				entry.URL = r.Request.RawURL
+			}
+			if r.Response != nil {
+				entry.StatusCode = r.StatusCode
+			}
+			if !r.Request.StartTime.IsZero() {
+				entry.Duration = time.Since(r.Request.StartTime)
+			}
+			logger(ctx, entry)
+			return nil
+		})
+		return nil
+	}
+}
+
+// Attribute is a key/value pair recorded on a span
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is the subset of a tracing span used by the client
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans for API requests. It is intentionally small so that an
+// OpenTelemetry tracer can be adapted without the SDK depending on otel.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span attribute keys
+const (
+	AttrHTTPMethod     = "http.request.method"
+	AttrHTTPRoute      = "http.route"
+	AttrHTTPStatusCode = "http.response.status_code"
+	AttrOperationName  = "gitness.operation"
+)
+
+// WithTracer wraps every request attempt in a span named "METHOD route",
+// where route is the API path with identifiers replaced by placeholders
+func WithTracer(tracer Tracer) ClientOptionFunc {
+	return func(c *Client) error {
+		if tracer == nil {
+			return nil
+		}
+		c.client.WrapRoundTripFunc(func(rt req.RoundTripper) req.RoundTripFunc {
+			return func(r *req.Request) (*req.Response, error) {
+				route := routeTemplate(r.URL.EscapedPath())
+				ctx, span := tracer.Start(r.Context(), r.Method+" "+route)
+				defer span.End()
+
+				attrs := []Attribute{
+					{Key: AttrHTTPMethod, Value: r.Method},
+					{Key: AttrHTTPRoute, Value: route},
+				}
+				if name := OperationName(ctx); name != "" {
+					attrs = append(attrs, Attribute{Key: AttrOperationName, Value: name})
+				}
+				span.SetAttributes(attrs...)
+
+				r.SetContext(ctx)
+				resp, err := rt.RoundTrip(r)
+				if resp != nil && resp.Response != nil {
+					span.SetAttributes(Attribute{Key: AttrHTTPStatusCode, Value: resp.StatusCode})
+				}
+				if err == nil && resp != nil {
+					err = resp.Err
+				}
+				if err != nil {
+					span.RecordError(err)
+				} else if resp != nil && resp.Response != nil && resp.StatusCode >= http.StatusBadRequest {
+					span.RecordError(&ErrorResponse{Response: resp, Message: resp.Status})
+				}
+				return resp, err
+			}
+		})
+		return nil
+	}
+}
+
+// routeCollections lists path segments that are followed by an identifier
+var routeCollections = map[string]bool{
+	"branches": true, "checks": true, "comments": true, "commits": true,
+	"connectors": true, "executions": true, "gitspaces": true, "infraproviders": true,
+	"labels": true, "memberships": true, "pipelines": true, "principals": true,
+	"pullreq": true, "repos": true, "reviewers": true, "rules": true,
+	"secrets": true, "spaces": true, "tags": true, "templates": true,
+	"tokens": true, "triggers": true, "users": true, "values": true,
+	"webhooks": true,
+}
+
+// routeRestSegments lists path segments followed by a repository file path
+var routeRestSegments = map[string]bool{
+	"blame": true, "content": true, "raw": true,
+}
+
+// routeTemplate replaces identifiers in an API path with placeholders so that
+// span names have low cardinality, e.g. /repos/{id}/pullreq/{id}/merge
+func routeTemplate(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	path = strings.TrimPrefix(path, apiVersionPath)
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i := 0; i < len(segments); i++ {
+		segment := segments[i]
+		if i > 0 && routeRestSegments[segments[i-1]] {
+			segments = append(segments[:i], "{path}")
+			break
+		}
+		if _, err := strconv.ParseInt(segment, 10, 64); err == nil ||
+			strings.Contains(segment, "%") ||
+			(i > 0 && routeCollections[segments[i-1]] && !routeCollections[segment]) {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// RequestMetric holds the numeric details of a single API request attempt
+type RequestMetric struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Duration   time.Duration
+	RetryCount int
+}
+
+// WithMetricsHook registers a hook that receives a RequestMetric for every
+// request attempt. Endpoint is the templated route, e.g. /repos/{id}/branches.
+func WithMetricsHook(hook func(m RequestMetric)) ClientOptionFunc {
+	return func(c *Client) error {
+		if hook == nil {
+			return nil
+		}
+		c.client.OnAfterResponse(func(_ *req.Client, r *req.Response) error {
+			if r.Request == nil || r.Request.URL == nil {
+				return nil
+			}
+			m := RequestMetric{
+				Method:     r.Request.Method,
+				Endpoint:   routeTemplate(r.Request.URL.EscapedPath()),
+				RetryCount: r.Request.RetryAttempt,
+			}
+			if r.Response != nil {
+				m.StatusCode = r.StatusCode
+			}
+			if !r.Request.StartTime.IsZero() {
+				m.Duration = time.Since(r.Request.StartTime)
+			}
+			hook(m)
+			return nil
+		})
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/v1/repos/space%2Frepo/pullreq/12/merge", "/repos/{id}/pullreq/{id}/merge"},
+		{"/api/v1/repos/space%2Frepo/branches/main", "/repos/{id}/branches/{id}"},
+		{"/api/v1/repos/space%2Frepo/content/docs/README.md", "/repos/{id}/content/{path}"},
+		{"/api/v1/spaces/team/repos", "/spaces/{id}/repos"},
+		{"/api/v1/system/config", "/system/config"},
+	}
+
+	for _, tt := range tests {
+		if got := routeTemplate(tt.path); got != tt.expected {
+			t.Errorf("routeTemplate(%q) = %q, expected %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+type recordingSpan struct {
+	name  string
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) { s.err = err }
+
+func (s *recordingSpan) End() { s.ended = true }
+
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name, attrs: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTracerRecordsSpans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"), WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, _, err := client.Repositories.GetBranch(context.Background(), "space/repo", "main"); err == nil {
+		t.Fatal("Expected error for 404 response")
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "GET /repos/{id}/branches/{id}" {
+		t.Errorf("Unexpected span name %q", span.name)
+	}
+	if span.attrs[AttrHTTPStatusCode] != http.StatusNotFound {
+		t.Errorf("Expected status attribute 404, got %v", span.attrs[AttrHTTPStatusCode])
+	}
+	if span.err == nil || !span.ended {
+		t.Errorf("Expected span to record an error and end, got %+v", span)
+	}
+}
+
+func TestMetricsHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var metrics []RequestMetric
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithMetricsHook(func(m RequestMetric) { metrics = append(metrics, m) }),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, _, err := client.PullRequests.GetPullRequest(context.Background(), "space/repo", 7); err != nil {
+		t.Fatalf("GetPullRequest returned error: %v", err)
+	}
+
+	if len(metrics) != 1 {
+		t.Fatalf("Expected 1 metric, got %d", len(metrics))
+	}
+	m := metrics[0]
+	if m.Method != http.MethodGet || m.Endpoint != "/repos/{id}/pullreq/{id}" || m.StatusCode != http.StatusOK || m.RetryCount != 0 {
+		t.Errorf("Unexpected metric: %+v", m)
+	}
+}
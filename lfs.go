@@ -0,0 +1,131 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// lfsScanConcurrency is the number of workers ListLFSObjects inspects files with
+const lfsScanConcurrency = 8
+
+// ErrNotLFSObject is returned by GetLFSObjectInfo when the file is not stored in Git LFS
+var ErrNotLFSObject = errors.New("file is not an LFS object")
+
+// LFSObject represents a file stored in Git LFS
+type LFSObject struct {
+	OID  *string `json:"oid,omitempty"`
+	Size *int64  `json:"size,omitempty"`
+	Path *string `json:"path,omitempty"`
+}
+
+// ListLFSObjectsOptions specifies options for listing LFS objects
+type ListLFSObjectsOptions struct {
+	GitRef *string `url:"git_ref,omitempty"`
+}
+
+// fileContentOutput represents the content endpoint output for a file
+type fileContentOutput struct {
+	Type    *string `json:"type,omitempty"`
+	Path    *string `json:"path,omitempty"`
+	Content *struct {
+		Size          *int64  `json:"size,omitempty"`
+		LFSObjectID   *string `json:"lfs_object_id,omitempty"`
+		LFSObjectSize *int64  `json:"lfs_object_size,omitempty"`
+	} `json:"content,omitempty"`
+}
+
+// GetLFSObjectInfo returns the LFS object backing a file. It returns
+// ErrNotLFSObject if the file is stored as a regular blob.
+func (s *RepositoriesService) GetLFSObjectInfo(ctx context.Context, repoPath, filePath string, gitRef *string) (*LFSObject, *Response, error) {
+	path := fmt.Sprintf("repos/%s/content/%s", url.PathEscape(repoPath), url.PathEscape(filePath))
+	req := s.client.newRequest(ctx).
+		SetQueryParam("include_commit", "false")
+	if gitRef != nil {
+		req.SetQueryParam("git_ref", *gitRef)
+	}
+
+	var content fileContentOutput
+	req.SetSuccessResult(&content)
+
+	resp, err := req.Get(s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if content.Content == nil || content.Content.LFSObjectID == nil || *content.Content.LFSObjectID == "" {
+		return nil, &Response{Response: resp}, fmt.Errorf("%w: %q", ErrNotLFSObject, filePath)
+	}
+
+	return &LFSObject{
+		OID:  content.Content.LFSObjectID,
+		Size: content.Content.LFSObjectSize,
+		Path: Ptr(filePath),
+	}, &Response{Response: resp}, nil
+}
+
+// ListLFSObjects lists the files in a repository that are stored in Git LFS.
+// Gitness has no LFS listing endpoint, so this lists the file tree with
+// ListPaths and inspects each file, making one request per file with at most
+// lfsScanConcurrency requests in flight.
+func (s *RepositoriesService) ListLFSObjects(ctx context.Context, repoPath string, opt *ListLFSObjectsOptions) ([]*LFSObject, *Response, error) {
+	var gitRef *string
+	if opt != nil {
+		gitRef = opt.GitRef
+	}
+
+	paths, response, err := s.ListPaths(ctx, repoPath, &ListPathsOptions{GitRef: gitRef})
+	if err != nil {
+		return nil, response, err
+	}
+
+	found := make([]*LFSObject, len(paths.Files))
+	errs := make([]error, len(paths.Files))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(lfsScanConcurrency, len(paths.Files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				file := paths.Files[i]
+				object, _, err := s.GetLFSObjectInfo(ctx, repoPath, file, gitRef)
+				if err != nil && !errors.Is(err, ErrNotLFSObject) {
+					errs[i] = fmt.Errorf("file %q: %w", file, err)
+					continue
+				}
+				found[i] = object
+			}
+		}()
+	}
+	for i := range paths.Files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, response, err
+	}
+
+	var objects []*LFSObject
+	for _, object := range found {
+		if object != nil {
+			objects = append(objects, object)
+		}
+	}
+	return objects, response, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestListLFSObjects tests that only LFS-backed files are returned with their object IDs and sizes
+func TestListLFSObjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if got := r.URL.Query().Get("git_ref"); got != "main" {
+			t.Errorf("Expected git_ref main, got %q", got)
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/paths"):
+			w.Write([]byte(`{"files":["README.md","assets/logo.png","data/model.bin"],"directories":["assets","data"]}`))
+		case strings.HasSuffix(r.URL.Path, "/content/README.md"):
+			w.Write([]byte(`{"type":"file","content":{"size":12,"data":"aGVsbG8="}}`))
+		case strings.HasSuffix(r.URL.Path, "/content/assets/logo.png"):
+			w.Write([]byte(`{"type":"file","content":{"size":130,"lfs_object_id":"abc","lfs_object_size":2048}}`))
+		case strings.HasSuffix(r.URL.Path, "/content/data/model.bin"):
+			w.Write([]byte(`{"type":"file","content":{"size":131,"lfs_object_id":"def","lfs_object_size":1048576}}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	objects, _, err := client.Repositories.ListLFSObjects(context.Background(), "space/repo", &ListLFSObjectsOptions{GitRef: Ptr("main")})
+	if err != nil {
+		t.Fatalf("ListLFSObjects returned error: %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("Expected 2 LFS objects, got %d", len(objects))
+	}
+	if *objects[0].Path != "assets/logo.png" || *objects[0].OID != "abc" || *objects[0].Size != 2048 {
+		t.Errorf("Unexpected first object: %+v", objects[0])
+	}
+	if *objects[1].Path != "data/model.bin" || *objects[1].Size != 1048576 {
+		t.Errorf("Unexpected second object: %+v", objects[1])
+	}
+
+	_, _, err = client.Repositories.GetLFSObjectInfo(context.Background(), "space/repo", "README.md", Ptr("main"))
+	if !errors.Is(err, ErrNotLFSObject) {
+		t.Errorf("Expected ErrNotLFSObject, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/imroc/req/v3"
+)
+
+// Page is a single page of list results together with its pagination metadata
+type Page[T any] struct {
+	Items      []*T
+	Page       int
+	PerPage    int
+	Total      int
+	TotalPages int
+	HasNext    bool
+
+	// Next fetches the following page. It returns nil, nil when HasNext is false.
+	Next func(ctx context.Context) (*Page[T], error)
+}
+
+// pageFetcher fetches a single page of a list endpoint
+type pageFetcher[T any] func(ctx context.Context, page int) ([]*T, *Response, error)
+
+// fetchPage fetches the given page (the first page when nil) and wires Next
+// to fetch the page after it
+func fetchPage[T any](ctx context.Context, page *int, fetch pageFetcher[T]) (*Page[T], error) {
+	current := 1
+	if page != nil && *page > 0 {
+		current = *page
+	}
+
+	items, resp, err := fetch(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Page[T]{
+		Items:   items,
+		Page:    current,
+		PerPage: len(items),
+	}
+	if resp.Page != nil {
+		p.Page = *resp.Page
+	}
+	if resp.PerPage != nil {
+		p.PerPage = *resp.PerPage
+	}
+	if resp.Total != nil {
+		p.Total = *resp.Total
+	}
+	if resp.TotalPages != nil {
+		p.TotalPages = *resp.TotalPages
+	}
+
+	next := 0
+	if resp.NextPage != nil && *resp.NextPage > p.Page {
+		next = *resp.NextPage
+	}
+	p.HasNext = next > 0
+	p.Next = func(ctx context.Context) (*Page[T], error) {
+		if next == 0 {
+			return nil, nil
+		}
+		return fetchPage(ctx, &next, fetch)
+	}
+
+	return p, nil
+}
+
+// ListAllOptions controls the ListAll helpers that follow pagination
+type ListAllOptions struct {
+	// MaxResults bounds the total number of items fetched; 0 means no limit.
+	// When the cap is hit before the last page, the returned Response has
+	// Truncated set.
+	MaxResults int
+}
+
+// maxResults returns the configured cap, 0 when opt is nil
+func (opt *ListAllOptions) maxResults() int {
+	if opt == nil || opt.MaxResults < 0 {
+		return 0
+	}
+	return opt.MaxResults
+}
+
+// listAll fetches every page of a list endpoint and returns the combined
+// items along with the response of the last page
+func listAll[T any](ctx context.Context, fetch pageFetcher[T]) ([]*T, *Response, error) {
+	return listAllMax(ctx, 0, fetch)
+}
+
+// listAllMax is like listAll but stops once maxResults items were collected,
+// marking the response as truncated if more items remained. A maxResults of
+// 0 fetches everything.
+func listAllMax[T any](ctx context.Context, maxResults int, fetch pageFetcher[T]) ([]*T, *Response, error) {
+	var all []*T
+	page := 1
+	for {
+		items, resp, err := fetch(ctx, page)
+		if err != nil {
+			return nil, resp, err
+		}
+		all = append(all, items...)
+
+		last := resp.NextPage == nil || *resp.NextPage <= page || len(items) == 0
+		if maxResults > 0 && len(all) >= maxResults {
+			resp.Truncated = len(all) > maxResults || !last
+			return all[:maxResults], resp, nil
+		}
+		if last {
+			return all, resp, nil
+		}
+		page = *resp.NextPage
+	}
+}
+
+// PageSizeParam names the query parameter an endpoint reads the page size from
+type PageSizeParam string
+
+// PageSizeParam constants
+const (
+	// PageSizeParamLimit is used by every paginated endpoint of the Gitness v1 API
+	PageSizeParamLimit PageSizeParam = "limit"
+	// PageSizeParamPerPage is used by servers or proxies that expect per_page
+	PageSizeParamPerPage PageSizeParam = "per_page"
+)
+
+// IsValid reports whether p is a known page size parameter
+func (p PageSizeParam) IsValid() bool {
+	switch p {
+	case PageSizeParamLimit, PageSizeParamPerPage:
+		return true
+	}
+	return false
+}
+
+// pageSizeOverride sends the page size as param on paths matching pattern
+type pageSizeOverride struct {
+	pattern string
+	param   PageSizeParam
+}
+
+// WithPageSizeParam sends ListOptions.Limit as param on endpoints whose path
+// below /api/v1 matches pattern, using path.Match syntax. Repository and
+// space paths are escaped into a single segment, so "repos/*/tags" matches
+// the tags of any repository. An endpoint that expects per_page silently
+// ignores limit and returns its default page size, so set an override for
+// it. The first matching override wins.
+func WithPageSizeParam(pattern string, param PageSizeParam) ClientOptionFunc {
+	return func(c *Client) error {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid page size pattern %q: %w", pattern, err)
+		}
+		if !param.IsValid() {
+			return fmt.Errorf("invalid page size parameter %q", param)
+		}
+		c.pageSizeParams = append(c.pageSizeParams, pageSizeOverride{pattern: pattern, param: param})
+		return nil
+	}
+}
+
+// pageSizeParam returns the page size parameter for the request URL rawURL
+func (c *Client) pageSizeParam(rawURL string) PageSizeParam {
+	if len(c.pageSizeParams) == 0 {
+		return PageSizeParamLimit
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return PageSizeParamLimit
+	}
+	escaped := u.EscapedPath()
+	i := strings.Index(escaped, "/"+apiVersionPath+"/")
+	if i < 0 {
+		return PageSizeParamLimit
+	}
+	apiPath := escaped[i+len(apiVersionPath)+2:]
+	for _, o := range c.pageSizeParams {
+		if ok, _ := path.Match(o.pattern, apiPath); ok {
+			return o.param
+		}
+	}
+	return PageSizeParamLimit
+}
+
+// setPageSizeParam renames the limit query parameter for endpoints with a
+// WithPageSizeParam override
+func (c *Client) setPageSizeParam(_ *req.Client, r *req.Request) error {
+	if !r.QueryParams.Has(string(PageSizeParamLimit)) {
+		return nil
+	}
+	param := c.pageSizeParam(r.RawURL)
+	if param == PageSizeParamLimit {
+		return nil
+	}
+	r.QueryParams[string(param)] = r.QueryParams[string(PageSizeParamLimit)]
+	r.QueryParams.Del(string(PageSizeParamLimit))
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListBranchesPaginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-page", strconv.Itoa(page))
+		w.Header().Set("x-per-page", "1")
+		w.Header().Set("x-total", "2")
+		w.Header().Set("x-total-pages", "2")
+		if page < 2 {
+			w.Header().Set("x-next-page", strconv.Itoa(page+1))
+		}
+		json.NewEncoder(w).Encode([]*Branch{{Name: Ptr("branch-" + strconv.Itoa(page))}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	page, err := client.Repositories.ListBranchesPaginated(ctx, "space/repo", &ListOptions{Limit: Ptr(1)})
+	if err != nil {
+		t.Fatalf("ListBranchesPaginated returned error: %v", err)
+	}
+	if page.Page != 1 || page.Total != 2 || page.TotalPages != 2 || !page.HasNext {
+		t.Errorf("Unexpected first page metadata: %+v", page)
+	}
+	if len(page.Items) != 1 || *page.Items[0].Name != "branch-1" {
+		t.Errorf("Unexpected first page items: %v", page.Items)
+	}
+
+	page, err = page.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if page.Page != 2 || page.HasNext || *page.Items[0].Name != "branch-2" {
+		t.Errorf("Unexpected second page: %+v", page)
+	}
+
+	page, err = page.Next(ctx)
+	if page != nil || err != nil {
+		t.Errorf("Expected nil page after the last one, got %+v, %v", page, err)
+	}
+}
+
+func TestListAllCommitsMaxResults(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Content-Type", "application/json")
+		if page < 3 {
+			w.Header().Set("x-next-page", strconv.Itoa(page+1))
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"commits": []*Commit{
+				{SHA: Ptr(strconv.Itoa(page) + "a")},
+				{SHA: Ptr(strconv.Itoa(page) + "b")},
+			},
+			"total_commits": 6,
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	commits, resp, err := client.Repositories.ListAllCommits(ctx, "space/repo", nil, &ListAllOptions{MaxResults: 3})
+	if err != nil {
+		t.Fatalf("ListAllCommits returned error: %v", err)
+	}
+	if len(commits) != 3 || *commits[2].SHA != "2a" || !resp.Truncated {
+		t.Errorf("Expected 3 commits and a truncated response, got %d, truncated=%v", len(commits), resp.Truncated)
+	}
+	if requests != 2 {
+		t.Errorf("Expected pagination to stop after 2 requests, got %d", requests)
+	}
+
+	commits, resp, err = client.Repositories.ListAllCommits(ctx, "space/repo", nil, &ListAllOptions{MaxResults: 6})
+	if err != nil {
+		t.Fatalf("ListAllCommits returned error: %v", err)
+	}
+	if len(commits) != 6 || resp.Truncated {
+		t.Errorf("Expected all 6 commits untruncated, got %d, truncated=%v", len(commits), resp.Truncated)
+	}
+
+	commits, _, err = client.Repositories.ListAllCommits(ctx, "space/repo", nil, nil)
+	if err != nil {
+		t.Fatalf("ListAllCommits returned error: %v", err)
+	}
+	if len(commits) != 6 {
+		t.Errorf("Expected 6 commits without a cap, got %d", len(commits))
+	}
+}
+
+func TestListMethodsParsePagination(t *testing.T) {
+	mux := http.NewServeMux()
+	for _, pattern := range []string{
+		"GET /api/v1/admin/audit",
+		"GET /api/v1/repos/{repo}/pipelines/{pipeline}/executions",
+		"GET /api/v1/repos/{repo}/commits",
+		"GET /api/v1/repos/{repo}/tags",
+		"GET /api/v1/gitspaces",
+		"GET /api/v1/spaces",
+	} {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("page"); got != "2" {
+				t.Errorf("%s: page = %q, want 2", r.URL.Path, got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("x-page", "2")
+			w.Header().Set("x-per-page", "1")
+			w.Header().Set("x-next-page", "3")
+			w.Header().Set("x-total", "5")
+			w.Header().Set("x-total-pages", "5")
+			if r.URL.Path == "/api/v1/admin/audit" {
+				_, _ = w.Write([]byte(`{"audit_logs": [{}], "page": 2, "size": 1, "total": 5}`))
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/commits") {
+				_, _ = w.Write([]byte(`{"commits": [{}], "total_commits": 5}`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{}]`))
+		})
+	}
+	client := NewTestClient(mux)
+	ctx := context.Background()
+	list := ListOptions{Page: Ptr(2), Limit: Ptr(1)}
+
+	tests := []struct {
+		name string
+		call func() (int, *Response, error)
+	}{
+		{"ListAuditLogs", func() (int, *Response, error) {
+			v, resp, err := client.Audit.ListAuditLogs(ctx, &ListAuditLogsOptions{ListOptions: list})
+			return len(v), resp, err
+		}},
+		{"ListPipelineExecutions", func() (int, *Response, error) {
+			v, resp, err := client.Pipelines.ListPipelineExecutions(ctx, "space/repo", "build", &ListPipelineExecutionsOptions{ListOptions: list})
+			return len(v), resp, err
+		}},
+		{"ListCommits", func() (int, *Response, error) {
+			v, resp, err := client.Repositories.ListCommits(ctx, "space/repo", &ListCommitsOptions{ListOptions: list})
+			return len(v), resp, err
+		}},
+		{"ListTags", func() (int, *Response, error) {
+			v, resp, err := client.Repositories.ListTags(ctx, "space/repo", &ListTagsOptions{ListOptions: list})
+			return len(v), resp, err
+		}},
+		{"ListGitspaces", func() (int, *Response, error) {
+			v, resp, err := client.Gitspaces.ListGitspaces(ctx, &ListGitspacesOptions{ListOptions: list})
+			return len(v), resp, err
+		}},
+		{"ListSpaces", func() (int, *Response, error) {
+			v, resp, err := client.Spaces.ListSpaces(ctx, &ListSpacesOptions{ListOptions: list})
+			return len(v), resp, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, resp, err := tt.call()
+			if err != nil {
+				t.Fatalf("returned error: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("got %d items, want 1", n)
+			}
+			if resp.Page == nil || *resp.Page != 2 || resp.NextPage == nil || *resp.NextPage != 3 ||
+				resp.Total == nil || *resp.Total != 5 || resp.TotalPages == nil || *resp.TotalPages != 5 {
+				t.Errorf("pagination = page %v next %v total %v pages %v", resp.Page, resp.NextPage, resp.Total, resp.TotalPages)
+			}
+		})
+	}
+}
+
+func TestWithPageSizeParam(t *testing.T) {
+	queries := make(map[string]url.Values)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/{kind}", func(w http.ResponseWriter, r *http.Request) {
+		queries[r.PathValue("kind")] = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	ctx := context.Background()
+	list := ListOptions{Limit: Ptr(25)}
+
+	client := NewTestClient(mux)
+	if _, _, err := client.Repositories.ListTags(ctx, "space/repo", &ListTagsOptions{ListOptions: list}); err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	if got := queries["tags"].Get("limit"); got != "25" {
+		t.Errorf("default: limit = %q, want 25", got)
+	}
+
+	client = NewTestClient(mux, WithPageSizeParam("repos/*/tags", PageSizeParamPerPage))
+	if _, _, err := client.Repositories.ListTags(ctx, "space/repo", &ListTagsOptions{ListOptions: list}); err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	if _, _, err := client.Repositories.ListBranches(ctx, "space/repo", &list); err != nil {
+		t.Fatalf("ListBranches returned error: %v", err)
+	}
+	if got := queries["tags"]; got.Get("per_page") != "25" || got.Has("limit") {
+		t.Errorf("tags override: query = %v, want per_page=25 only", got)
+	}
+	if got := queries["branches"]; got.Get("limit") != "25" || got.Has("per_page") {
+		t.Errorf("branches: query = %v, want limit=25 only", got)
+	}
+
+	if _, err := NewClient("token", WithPageSizeParam("repos/[", PageSizeParamPerPage)); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+	if _, err := NewClient("token", WithPageSizeParam("repos/*/tags", "size")); err == nil {
+		t.Error("expected error for unknown parameter")
+	}
+}
@@ -8,8 +8,11 @@ package gitness
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"sort"
+	"sync"
 )
 
 // PipelinesService handles communication with pipeline related methods
@@ -33,11 +36,51 @@ type Pipeline struct {
 	Version       *int64  `json:"version,omitempty"`
 }
 
+// ExecutionStatus represents the status of a pipeline execution
+type ExecutionStatus string
+
+// ExecutionStatus constants
+const (
+	ExecutionStatusPending               ExecutionStatus = "pending"
+	ExecutionStatusWaitingOnDependencies ExecutionStatus = "waiting_on_dependencies"
+	ExecutionStatusBlocked               ExecutionStatus = "blocked"
+	ExecutionStatusRunning               ExecutionStatus = "running"
+	ExecutionStatusSuccess               ExecutionStatus = "success"
+	ExecutionStatusFailure               ExecutionStatus = "failure"
+	ExecutionStatusError                 ExecutionStatus = "error"
+	ExecutionStatusKilled                ExecutionStatus = "killed"
+	ExecutionStatusSkipped               ExecutionStatus = "skipped"
+	ExecutionStatusDeclined              ExecutionStatus = "declined"
+)
+
+// IsValid reports whether st is a status known to Gitness
+func (st ExecutionStatus) IsValid() bool {
+	switch st {
+	case ExecutionStatusPending, ExecutionStatusWaitingOnDependencies, ExecutionStatusBlocked,
+		ExecutionStatusRunning, ExecutionStatusSuccess, ExecutionStatusFailure, ExecutionStatusError,
+		ExecutionStatusKilled, ExecutionStatusSkipped, ExecutionStatusDeclined:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal reports whether an execution in status st has finished and will not change
+func (st ExecutionStatus) IsTerminal() bool {
+	switch st {
+	case ExecutionStatusSuccess, ExecutionStatusFailure, ExecutionStatusError,
+		ExecutionStatusKilled, ExecutionStatusSkipped, ExecutionStatusDeclined:
+		return true
+	default:
+		return false
+	}
+}
+
 // PipelineExecution represents a pipeline execution
 type PipelineExecution struct {
 	Number       *int64            `json:"number,omitempty"`
 	PipelineID   *int64            `json:"pipeline_id,omitempty"`
-	Status       *string           `json:"status,omitempty"`
+	Status       *ExecutionStatus  `json:"status,omitempty"`
 	Event        *string           `json:"event,omitempty"`
 	Action       *string           `json:"action,omitempty"`
 	Ref          *string           `json:"ref,omitempty"`
@@ -56,6 +99,72 @@ type PipelineExecution struct {
 	Created      *int64            `json:"created,omitempty"`
 	Updated      *int64            `json:"updated,omitempty"`
 	Params       map[string]string `json:"params,omitempty"`
+	Stages       []*ExecutionStage `json:"stages,omitempty"`
+}
+
+// ExecutionStage represents a stage of a pipeline execution
+type ExecutionStage struct {
+	Number      *int64            `json:"number,omitempty"`
+	ExecutionID *int64            `json:"execution_id,omitempty"`
+	RepoID      *int64            `json:"repo_id,omitempty"`
+	Name        *string           `json:"name,omitempty"`
+	Kind        *string           `json:"kind,omitempty"`
+	Type        *string           `json:"type,omitempty"`
+	Status      *ExecutionStatus  `json:"status,omitempty"`
+	Error       *string           `json:"error,omitempty"`
+	ErrIgnore   *bool             `json:"errignore,omitempty"`
+	ExitCode    *int              `json:"exit_code,omitempty"`
+	Machine     *string           `json:"machine,omitempty"`
+	OS          *string           `json:"os,omitempty"`
+	Arch        *string           `json:"arch,omitempty"`
+	Variant     *string           `json:"variant,omitempty"`
+	Kernel      *string           `json:"kernel,omitempty"`
+	Limit       *int              `json:"limit,omitempty"`
+	Throttle    *int              `json:"throttle,omitempty"`
+	Started     *int64            `json:"started,omitempty"`
+	Stopped     *int64            `json:"stopped,omitempty"`
+	OnSuccess   *bool             `json:"on_success,omitempty"`
+	OnFailure   *bool             `json:"on_failure,omitempty"`
+	DependsOn   []string          `json:"depends_on,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Steps       []*ExecutionStep  `json:"steps,omitempty"`
+}
+
+// ExecutionStep represents a step within an execution stage
+type ExecutionStep struct {
+	Number    *int64           `json:"number,omitempty"`
+	Name      *string          `json:"name,omitempty"`
+	Image     *string          `json:"image,omitempty"`
+	Schema    *string          `json:"schema,omitempty"`
+	Status    *ExecutionStatus `json:"status,omitempty"`
+	Error     *string          `json:"error,omitempty"`
+	ErrIgnore *bool            `json:"errignore,omitempty"`
+	ExitCode  *int             `json:"exit_code,omitempty"`
+	Detached  *bool            `json:"detached,omitempty"`
+	Started   *int64           `json:"started,omitempty"`
+	Stopped   *int64           `json:"stopped,omitempty"`
+	DependsOn []string         `json:"depends_on,omitempty"`
+}
+
+// IsTerminal reports whether the execution has finished
+func (e *PipelineExecution) IsTerminal() bool {
+	return e.Status != nil && e.Status.IsTerminal()
+}
+
+// IsSuccess reports whether the execution finished successfully
+func (e *PipelineExecution) IsSuccess() bool {
+	return e.Status != nil && *e.Status == ExecutionStatusSuccess
+}
+
+// FailedStages returns the stages that finished with status failure or error
+func (e *PipelineExecution) FailedStages() []*ExecutionStage {
+	var failed []*ExecutionStage
+	for _, stage := range e.Stages {
+		if stage.Status != nil && (*stage.Status == ExecutionStatusFailure || *stage.Status == ExecutionStatusError) {
+			failed = append(failed, stage)
+		}
+	}
+	return failed
 }
 
 // TriggerAction defines the different actions on triggers will fire
@@ -130,7 +239,7 @@ type UpdatePipelineTriggerOptions struct {
 // ListPipelineExecutionsOptions specifies options for listing pipeline executions
 type ListPipelineExecutionsOptions struct {
 	ListOptions
-	Status *string `url:"status,omitempty"`
+	Status *ExecutionStatus `url:"status,omitempty"`
 }
 
 // CreatePipelineOptions specifies options for creating a pipeline
@@ -181,7 +290,7 @@ func (s *PipelinesService) CreatePipeline(ctx context.Context, repoPath string,
 
 // GetPipeline retrieves a specific pipeline
 func (s *PipelinesService) GetPipeline(ctx context.Context, repoPath, pipelineID string) (*Pipeline, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s", url.PathEscape(repoPath), pipelineID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s", url.PathEscape(repoPath), url.PathEscape(pipelineID))
 	var pipeline Pipeline
 	resp, err := s.client.Get(ctx, path, &pipeline)
 	if err != nil {
@@ -190,9 +299,14 @@ func (s *PipelinesService) GetPipeline(ctx context.Context, repoPath, pipelineID
 	return &pipeline, resp, nil
 }
 
+// GetPipelineOrNil is like GetPipeline but returns a nil Pipeline without an error when the pipeline does not exist
+func (s *PipelinesService) GetPipelineOrNil(ctx context.Context, repoPath, pipelineID string) (*Pipeline, *Response, error) {
+	return notFoundAsNil(s.GetPipeline(ctx, repoPath, pipelineID))
+}
+
 // UpdatePipeline updates a pipeline
 func (s *PipelinesService) UpdatePipeline(ctx context.Context, repoPath, pipelineID string, opt *UpdatePipelineOptions) (*Pipeline, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s", url.PathEscape(repoPath), pipelineID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s", url.PathEscape(repoPath), url.PathEscape(pipelineID))
 	var pipeline Pipeline
 	resp, err := s.client.Patch(ctx, path, opt, &pipeline)
 	if err != nil {
@@ -203,48 +317,162 @@ func (s *PipelinesService) UpdatePipeline(ctx context.Context, repoPath, pipelin
 
 // DeletePipeline deletes a pipeline
 func (s *PipelinesService) DeletePipeline(ctx context.Context, repoPath, pipelineID string) (*Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s", url.PathEscape(repoPath), pipelineID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s", url.PathEscape(repoPath), url.PathEscape(pipelineID))
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
 
 // ListPipelineExecutions lists executions for a pipeline
 func (s *PipelinesService) ListPipelineExecutions(ctx context.Context, repoPath, pipelineID string, opt *ListPipelineExecutionsOptions) ([]*PipelineExecution, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions", url.PathEscape(repoPath), pipelineID)
-	req := s.client.client.R().SetContext(ctx)
+	if opt == nil {
+		opt = &ListPipelineExecutionsOptions{}
+	}
 
-	// Add query parameters if options provided
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions", url.PathEscape(repoPath), url.PathEscape(pipelineID))
 
+	var executions []*PipelineExecution
+	resp, err := s.client.performListRequestWithParams(ctx, path, func(req httpRequest) {
+		s.client.buildQueryParams(req, &opt.ListOptions)
 		if opt.Status != nil {
-			req.SetQueryParam("status", *opt.Status)
+			req.SetQueryParam("status", string(*opt.Status))
 		}
+	}, &executions)
+	if err != nil {
+		return nil, resp, err
 	}
 
-	var executions []*PipelineExecution
-	req.SetSuccessResult(&executions)
+	return executions, resp, nil
+}
 
-	fullURL := s.client.buildFullURL(path)
-	resp, err := req.Get(fullURL)
+// ListRepositoryExecutionsOptions specifies options for listing executions across a repository
+type ListRepositoryExecutionsOptions struct {
+	ListOptions
+	Status *ExecutionStatus `url:"status,omitempty"`
+}
+
+// maxExecutionsPageSize is the largest page requested per pipeline by ListRepositoryExecutions
+const maxExecutionsPageSize = 100
+
+// executionsFanOutConcurrency bounds the pipelines ListRepositoryExecutions lists at once
+const executionsFanOutConcurrency = 8
+
+// ListRepositoryExecutions lists executions of every pipeline in a
+// repository, newest first. Gitness only lists executions per pipeline, so
+// this fans out over the repository's pipelines, with at most
+// executionsFanOutConcurrency in flight, and merges the results.
+// Page and Limit select a window of the merged list (default limit 20).
+//
+// The returned Response wraps the last pipeline list response and carries the
+// pagination state of the merged list: Page, PerPage and NextPage are always
+// set, Total and TotalPages only when every pipeline reported its total.
+func (s *PipelinesService) ListRepositoryExecutions(ctx context.Context, repoPath string, opt *ListRepositoryExecutionsOptions) ([]*PipelineExecution, *Response, error) {
+	page, limit := 1, 20
+	var status *ExecutionStatus
+	if opt != nil {
+		if opt.Page != nil && *opt.Page > 0 {
+			page = *opt.Page
+		}
+		if opt.Limit != nil && *opt.Limit > 0 {
+			limit = *opt.Limit
+		}
+		status = opt.Status
+	}
+	// Each pipeline contributes at most this many executions to the window
+	need := page * limit
+
+	pipelines, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*Pipeline, *Response, error) {
+		return s.ListPipelines(ctx, repoPath, &ListOptions{Page: Ptr(page), Limit: Ptr(maxExecutionsPageSize)})
+	})
 	if err != nil {
-		return nil, &Response{Response: resp}, err
+		return nil, resp, fmt.Errorf("list pipelines: %w", err)
 	}
 
-	if err := s.client.checkResponse(resp); err != nil {
-		return nil, &Response{Response: resp}, err
+	var identifiers []string
+	for _, pipeline := range pipelines {
+		if pipeline.Identifier != nil {
+			identifiers = append(identifiers, *pipeline.Identifier)
+		}
 	}
 
-	response := &Response{Response: resp}
-	s.client.parsePaginationHeaders(response)
+	found := make([][]*PipelineExecution, len(identifiers))
+	responses := make([]*Response, len(identifiers))
+	errs := make([]error, len(identifiers))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(executionsFanOutConcurrency, len(identifiers)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				found[i], responses[i], errs[i] = s.recentExecutions(ctx, repoPath, identifiers[i], status, need)
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("list executions of pipeline %q: %w", identifiers[i], errs[i])
+				}
+			}
+		}()
+	}
+	for i := range identifiers {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, resp, err
+	}
+
+	var executions []*PipelineExecution
+	more := false
+	total, totalKnown := 0, true
+	for i := range identifiers {
+		executions = append(executions, found[i]...)
+		more = more || responses[i].Truncated
+		if responses[i].Total == nil {
+			totalKnown = false
+		} else {
+			total += *responses[i].Total
+		}
+	}
+
+	sort.SliceStable(executions, func(i, j int) bool {
+		ci, cj := executions[i].Created, executions[j].Created
+		if ci == nil || cj == nil {
+			return ci != nil
+		}
+		return *ci > *cj
+	})
+
+	response := &Response{Response: resp.Response, Page: Ptr(page), PerPage: Ptr(limit)}
+	start := (page - 1) * limit
+	if more || start+limit < len(executions) {
+		response.NextPage = Ptr(page + 1)
+	}
+	if totalKnown {
+		response.Total = Ptr(total)
+		response.TotalPages = Ptr((total + limit - 1) / limit)
+	}
+	if start >= len(executions) {
+		return nil, response, nil
+	}
+	return executions[start:min(start+limit, len(executions))], response, nil
+}
 
-	return executions, response, nil
+// recentExecutions returns up to n of a pipeline's most recent executions.
+// The response is marked as truncated if the pipeline has more.
+func (s *PipelinesService) recentExecutions(ctx context.Context, repoPath, pipelineID string, status *ExecutionStatus, n int) ([]*PipelineExecution, *Response, error) {
+	return listAllMax(ctx, n, func(ctx context.Context, page int) ([]*PipelineExecution, *Response, error) {
+		return s.ListPipelineExecutions(ctx, repoPath, pipelineID, &ListPipelineExecutionsOptions{
+			ListOptions: ListOptions{Page: Ptr(page), Limit: Ptr(min(n, maxExecutionsPageSize))},
+			Status:      status,
+		})
+	})
 }
 
 // CreateExecution creates/triggers a new pipeline execution
 func (s *PipelinesService) CreateExecution(ctx context.Context, repoPath, pipelineID string, branch *string) (*PipelineExecution, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions", url.PathEscape(repoPath), pipelineID)
-	req := s.client.client.R().SetContext(ctx)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions", url.PathEscape(repoPath), url.PathEscape(pipelineID))
+	req := s.client.newRequest(ctx)
 
 	if branch != nil {
 		req.SetQueryParam("branch", *branch)
@@ -268,7 +496,7 @@ func (s *PipelinesService) CreateExecution(ctx context.Context, repoPath, pipeli
 
 // GetPipelineExecution retrieves a specific pipeline execution
 func (s *PipelinesService) GetPipelineExecution(ctx context.Context, repoPath, pipelineID string, executionNumber int64) (*PipelineExecution, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d", url.PathEscape(repoPath), pipelineID, executionNumber)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d", url.PathEscape(repoPath), url.PathEscape(pipelineID), executionNumber)
 	var execution PipelineExecution
 	resp, err := s.client.Get(ctx, path, &execution)
 	if err != nil {
@@ -279,21 +507,21 @@ func (s *PipelinesService) GetPipelineExecution(ctx context.Context, repoPath, p
 
 // DeleteExecution deletes a pipeline execution
 func (s *PipelinesService) DeleteExecution(ctx context.Context, repoPath, pipelineID string, executionNumber int64) (*Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d", url.PathEscape(repoPath), pipelineID, executionNumber)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d", url.PathEscape(repoPath), url.PathEscape(pipelineID), executionNumber)
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
 
 // CancelPipelineExecution cancels a pipeline execution
 func (s *PipelinesService) CancelPipelineExecution(ctx context.Context, repoPath, pipelineID string, executionNumber int64) (*Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d/cancel", url.PathEscape(repoPath), pipelineID, executionNumber)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d/cancel", url.PathEscape(repoPath), url.PathEscape(pipelineID), executionNumber)
 	resp, err := s.client.Post(ctx, path, nil, nil)
 	return resp, err
 }
 
 // RetryPipelineExecution retries a pipeline execution
 func (s *PipelinesService) RetryPipelineExecution(ctx context.Context, repoPath, pipelineID string, executionNumber int64) (*PipelineExecution, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d/retry", url.PathEscape(repoPath), pipelineID, executionNumber)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d/retry", url.PathEscape(repoPath), url.PathEscape(pipelineID), executionNumber)
 	var execution PipelineExecution
 	resp, err := s.client.Post(ctx, path, nil, &execution)
 	if err != nil {
@@ -304,7 +532,7 @@ func (s *PipelinesService) RetryPipelineExecution(ctx context.Context, repoPath,
 
 // ListPipelineTriggers lists triggers for a pipeline
 func (s *PipelinesService) ListPipelineTriggers(ctx context.Context, repoPath, pipelineID string, opt *ListOptions) ([]*PipelineTrigger, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers", url.PathEscape(repoPath), pipelineID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers", url.PathEscape(repoPath), url.PathEscape(pipelineID))
 	var triggers []*PipelineTrigger
 	resp, err := s.client.performListRequest(ctx, path, opt, &triggers)
 	if err != nil {
@@ -315,7 +543,7 @@ func (s *PipelinesService) ListPipelineTriggers(ctx context.Context, repoPath, p
 
 // CreatePipelineTrigger creates a trigger for a pipeline
 func (s *PipelinesService) CreatePipelineTrigger(ctx context.Context, repoPath, pipelineID string, opt *CreatePipelineTriggerOptions) (*PipelineTrigger, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers", url.PathEscape(repoPath), pipelineID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers", url.PathEscape(repoPath), url.PathEscape(pipelineID))
 	var trigger PipelineTrigger
 	resp, err := s.client.Post(ctx, path, opt, &trigger)
 	if err != nil {
@@ -326,7 +554,7 @@ func (s *PipelinesService) CreatePipelineTrigger(ctx context.Context, repoPath,
 
 // GetPipelineTrigger retrieves a specific pipeline trigger
 func (s *PipelinesService) GetPipelineTrigger(ctx context.Context, repoPath, pipelineID, triggerID string) (*PipelineTrigger, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers/%s", url.PathEscape(repoPath), pipelineID, triggerID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers/%s", url.PathEscape(repoPath), url.PathEscape(pipelineID), url.PathEscape(triggerID))
 	var trigger PipelineTrigger
 	resp, err := s.client.Get(ctx, path, &trigger)
 	if err != nil {
@@ -337,7 +565,7 @@ func (s *PipelinesService) GetPipelineTrigger(ctx context.Context, repoPath, pip
 
 // UpdatePipelineTrigger updates a pipeline trigger
 func (s *PipelinesService) UpdatePipelineTrigger(ctx context.Context, repoPath, pipelineID, triggerID string, opt *UpdatePipelineTriggerOptions) (*PipelineTrigger, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers/%s", url.PathEscape(repoPath), pipelineID, triggerID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers/%s", url.PathEscape(repoPath), url.PathEscape(pipelineID), url.PathEscape(triggerID))
 	var trigger PipelineTrigger
 	resp, err := s.client.Patch(ctx, path, opt, &trigger)
 	if err != nil {
@@ -348,14 +576,14 @@ func (s *PipelinesService) UpdatePipelineTrigger(ctx context.Context, repoPath,
 
 // DeletePipelineTrigger deletes a pipeline trigger
 func (s *PipelinesService) DeletePipelineTrigger(ctx context.Context, repoPath, pipelineID, triggerID string) (*Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers/%s", url.PathEscape(repoPath), pipelineID, triggerID)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/triggers/%s", url.PathEscape(repoPath), url.PathEscape(pipelineID), url.PathEscape(triggerID))
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
 
 // ViewExecutionLogs retrieves logs for a specific step in an execution
 func (s *PipelinesService) ViewExecutionLogs(ctx context.Context, repoPath, pipelineID string, executionNumber, stageNumber, stepNumber int64) ([]*LogLine, *Response, error) {
-	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d/logs/%d/%d", url.PathEscape(repoPath), pipelineID, executionNumber, stageNumber, stepNumber)
+	path := fmt.Sprintf("repos/%s/pipelines/%s/executions/%d/logs/%d/%d", url.PathEscape(repoPath), url.PathEscape(pipelineID), executionNumber, stageNumber, stepNumber)
 	var logs []*LogLine
 	resp, err := s.client.Get(ctx, path, &logs)
 	if err != nil {
@@ -0,0 +1,192 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// TestUpdatePipelineReEnable tests that a disabled pipeline can be re-enabled with Disabled=false
+func TestUpdatePipelineReEnable(t *testing.T) {
+	pipeline := &Pipeline{Identifier: Ptr("build"), Disabled: Ptr(true)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if disabled, ok := body["disabled"].(bool); ok {
+			pipeline.Disabled = Ptr(disabled)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pipeline)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	updated, _, err := client.Pipelines.UpdatePipeline(context.Background(), "space/repo", "build", &UpdatePipelineOptions{
+		Disabled: Ptr(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdatePipeline returned error: %v", err)
+	}
+
+	if updated.Disabled == nil || *updated.Disabled {
+		t.Errorf("Expected pipeline to be enabled, got disabled=%v", updated.Disabled)
+	}
+}
+
+// TestListRepositoryExecutions tests that executions from all pipelines are merged newest first and paged
+func TestListRepositoryExecutions(t *testing.T) {
+	executions := map[string][]*PipelineExecution{
+		"build":  {{Number: Ptr(int64(2)), Created: Ptr(int64(400))}, {Number: Ptr(int64(1)), Created: Ptr(int64(100))}},
+		"deploy": {{Number: Ptr(int64(2)), Created: Ptr(int64(300))}, {Number: Ptr(int64(1)), Created: Ptr(int64(200))}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*Pipeline{{Identifier: Ptr("build")}, {Identifier: Ptr("deploy")}})
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pipelines/{pipeline}/executions", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("status"); got != "success" {
+			t.Errorf("Expected status filter success, got %q", got)
+		}
+		items := executions[r.PathValue("pipeline")]
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := min((page-1)*limit, len(items))
+		end := min(start+limit, len(items))
+		if end < len(items) {
+			w.Header().Set("x-next-page", strconv.Itoa(page+1))
+		}
+		w.Header().Set("x-total", strconv.Itoa(len(items)))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(items[start:end])
+	})
+	client := NewTestClient(mux)
+
+	opt := &ListRepositoryExecutionsOptions{
+		ListOptions: ListOptions{Page: Ptr(1), Limit: Ptr(3)},
+		Status:      Ptr(ExecutionStatusSuccess),
+	}
+	got, resp, err := client.Pipelines.ListRepositoryExecutions(context.Background(), "space/repo", opt)
+	if err != nil {
+		t.Fatalf("ListRepositoryExecutions returned error: %v", err)
+	}
+	if *resp.Page != 1 || *resp.PerPage != 3 || resp.NextPage == nil || *resp.NextPage != 2 ||
+		*resp.Total != 4 || *resp.TotalPages != 2 {
+		t.Errorf("Unexpected pagination page=%v per_page=%v next=%v total=%v pages=%v",
+			resp.Page, resp.PerPage, resp.NextPage, resp.Total, resp.TotalPages)
+	}
+	want := []int64{400, 300, 200}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d executions, got %d", len(want), len(got))
+	}
+	for i, created := range want {
+		if *got[i].Created != created {
+			t.Errorf("Execution %d: expected created %d, got %d", i, created, *got[i].Created)
+		}
+	}
+
+	opt.Page = Ptr(2)
+	got, resp, err = client.Pipelines.ListRepositoryExecutions(context.Background(), "space/repo", opt)
+	if err != nil {
+		t.Fatalf("ListRepositoryExecutions returned error: %v", err)
+	}
+	if len(got) != 1 || *got[0].Created != 100 {
+		t.Errorf("Expected only the oldest execution on page 2, got %d executions", len(got))
+	}
+	if resp.NextPage != nil {
+		t.Errorf("Expected no next page after the last page, got %d", *resp.NextPage)
+	}
+
+	// A window smaller than a pipeline's history leaves executions unfetched,
+	// which must still be reported as a next page
+	got, resp, err = client.Pipelines.ListRepositoryExecutions(context.Background(), "space/repo", &ListRepositoryExecutionsOptions{
+		ListOptions: ListOptions{Page: Ptr(1), Limit: Ptr(1)},
+		Status:      Ptr(ExecutionStatusSuccess),
+	})
+	if err != nil {
+		t.Fatalf("ListRepositoryExecutions returned error: %v", err)
+	}
+	if len(got) != 1 || *got[0].Created != 400 {
+		t.Errorf("Expected the newest execution, got %d executions", len(got))
+	}
+	if resp.NextPage == nil || *resp.NextPage != 2 {
+		t.Errorf("Expected next page 2, got %v", resp.NextPage)
+	}
+}
+
+func TestExecutionStatusHelpers(t *testing.T) {
+	tests := []struct {
+		status   ExecutionStatus
+		terminal bool
+		success  bool
+	}{
+		{ExecutionStatusPending, false, false},
+		{ExecutionStatusBlocked, false, false},
+		{ExecutionStatusRunning, false, false},
+		{ExecutionStatusSuccess, true, true},
+		{ExecutionStatusFailure, true, false},
+		{ExecutionStatusKilled, true, false},
+		{ExecutionStatusSkipped, true, false},
+	}
+	for _, tt := range tests {
+		execution := &PipelineExecution{Status: Ptr(tt.status)}
+		if execution.IsTerminal() != tt.terminal {
+			t.Errorf("%s: expected IsTerminal %v", tt.status, tt.terminal)
+		}
+		if execution.IsSuccess() != tt.success {
+			t.Errorf("%s: expected IsSuccess %v", tt.status, tt.success)
+		}
+		if !tt.status.IsValid() {
+			t.Errorf("%s: expected IsValid", tt.status)
+		}
+	}
+
+	if ExecutionStatus("sucess").IsValid() {
+		t.Error("Expected misspelled status to be invalid")
+	}
+	if (&PipelineExecution{}).IsTerminal() {
+		t.Error("Expected execution without status not to be terminal")
+	}
+}
+
+// TestExecutionStages tests decoding the stages of an execution
+func TestExecutionStages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pipelines/{pipeline}/executions/{number}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":3,"status":"failure","stages":[
+			{"number":1,"name":"build","status":"success"},
+			{"number":2,"name":"test","status":"failure","steps":[{"number":1,"name":"go test","status":"failure","exit_code":1}]}]}`))
+	})
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	execution, _, err := client.Pipelines.GetPipelineExecution(ctx, "space/repo", "ci", 3)
+	if err != nil {
+		t.Fatalf("GetPipelineExecution returned error: %v", err)
+	}
+	failed := execution.FailedStages()
+	if len(failed) != 1 || *failed[0].Name != "test" || *failed[0].Steps[0].ExitCode != 1 {
+		t.Fatalf("Expected the test stage to have failed, got %d failed stages", len(failed))
+	}
+}
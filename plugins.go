@@ -32,7 +32,7 @@ type Plugin struct {
 // ListPlugins lists all plugins
 func (s *PluginsService) ListPlugins(ctx context.Context) ([]*Plugin, *Response, error) {
 	var plugins []*Plugin
-	resp, err := s.client.Get(ctx, "plugins", &plugins)
+	resp, err := s.client.performListRequest(ctx, "plugins", nil, &plugins)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -8,48 +8,63 @@ package gitness
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 )
 
+// ErrPrincipalNotFound is returned when no principal matches a lookup
+var ErrPrincipalNotFound = errors.New("principal not found")
+
 // PrincipalsService handles communication with principals related methods
 type PrincipalsService struct {
 	client *Client
 }
 
+// PrincipalType is the kind of a principal
+type PrincipalType string
+
+// PrincipalType constants
+const (
+	PrincipalTypeUser           PrincipalType = "user"
+	PrincipalTypeServiceAccount PrincipalType = "serviceaccount"
+	PrincipalTypeService        PrincipalType = "service"
+)
+
 // Principal represents a Gitness principal (user or service account)
 type Principal struct {
-	ID          *int64  `json:"id,omitempty"`
-	Type        *string `json:"type,omitempty"`
-	UID         *string `json:"uid,omitempty"`
-	DisplayName *string `json:"display_name,omitempty"`
-	Email       *string `json:"email,omitempty"`
-	Created     *Time   `json:"created,omitempty"`
-	Updated     *Time   `json:"updated,omitempty"`
+	ID          *int64         `json:"id,omitempty"`
+	Type        *PrincipalType `json:"type,omitempty"`
+	UID         *string        `json:"uid,omitempty"`
+	DisplayName *string        `json:"display_name,omitempty"`
+	Email       *string        `json:"email,omitempty"`
+	Created     *Time          `json:"created,omitempty"`
+	Updated     *Time          `json:"updated,omitempty"`
 }
 
 // ListPrincipalsOptions specifies options for listing principals
 type ListPrincipalsOptions struct {
 	ListOptions
-	Type *string `url:"type,omitempty"`
+	Type *PrincipalType `url:"type,omitempty"`
 }
 
 // ListPrincipals lists all principals
 func (s *PrincipalsService) ListPrincipals(ctx context.Context, opt *ListPrincipalsOptions) ([]*Principal, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+	if opt == nil {
+		opt = &ListPrincipalsOptions{}
+	}
 
-	// Add query parameters if options provided
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	req := s.client.newRequest(ctx)
 
-		if opt.Type != nil {
-			req.SetQueryParam("type", *opt.Type)
-		}
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.Type != nil {
+		req.SetQueryParam("type", string(*opt.Type))
 	}
 
 	var principals []*Principal
 	req.SetSuccessResult(&principals)
 
-	resp, err := req.Get("principals")
+	resp, err := req.Get(s.client.buildFullURL("principals"))
 	if err != nil {
 		return nil, &Response{Response: resp}, err
 	}
@@ -74,3 +89,39 @@ func (s *PrincipalsService) GetPrincipal(ctx context.Context, principalID int64)
 	}
 	return &principal, resp, nil
 }
+
+// findPrincipal searches principals matching query and returns the first one accepted by match
+func (s *PrincipalsService) findPrincipal(ctx context.Context, query string, match func(*Principal) bool) (*Principal, *Response, error) {
+	opt := &ListPrincipalsOptions{
+		ListOptions: ListOptions{Page: Ptr(1), Limit: Ptr(100), Query: Ptr(query)},
+	}
+	for {
+		principals, resp, err := s.ListPrincipals(ctx, opt)
+		if err != nil {
+			return nil, resp, err
+		}
+		for _, principal := range principals {
+			if match(principal) {
+				return principal, resp, nil
+			}
+		}
+		if resp.NextPage == nil || *resp.NextPage <= *opt.Page || len(principals) == 0 {
+			return nil, resp, ErrPrincipalNotFound
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// FindPrincipalByEmail returns the principal with the given email address, compared case-insensitively
+func (s *PrincipalsService) FindPrincipalByEmail(ctx context.Context, email string) (*Principal, *Response, error) {
+	return s.findPrincipal(ctx, email, func(p *Principal) bool {
+		return p.Email != nil && strings.EqualFold(*p.Email, email)
+	})
+}
+
+// FindPrincipalByUID returns the principal with the given UID
+func (s *PrincipalsService) FindPrincipalByUID(ctx context.Context, uid string) (*Principal, *Response, error) {
+	return s.findPrincipal(ctx, uid, func(p *Principal) bool {
+		return p.UID != nil && *p.UID == uid
+	})
+}
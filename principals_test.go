@@ -0,0 +1,49 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFindPrincipalByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("query"); got != "jane@example.com" && got != "nobody@example.com" {
+			t.Errorf("Unexpected query %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("x-next-page", "2")
+			w.Write([]byte(`[{"id":1,"uid":"janet","email":"janet@example.com"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":2,"uid":"jane","email":"Jane@Example.com"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	principal, _, err := client.Principals.FindPrincipalByEmail(context.Background(), "jane@example.com")
+	if err != nil {
+		t.Fatalf("FindPrincipalByEmail returned error: %v", err)
+	}
+	if *principal.ID != 2 {
+		t.Errorf("Expected principal 2, got %d", *principal.ID)
+	}
+
+	_, _, err = client.Principals.FindPrincipalByEmail(context.Background(), "nobody@example.com")
+	if !errors.Is(err, ErrPrincipalNotFound) {
+		t.Errorf("Expected ErrPrincipalNotFound, got %v", err)
+	}
+}
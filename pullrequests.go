@@ -8,7 +8,9 @@ package gitness
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 )
 
@@ -31,9 +33,10 @@ type PullRequest struct {
 	Description      *string           `json:"description,omitempty"`
 	SourceRepoID     *int64            `json:"source_repo_id,omitempty"`
 	SourceBranch     *string           `json:"source_branch,omitempty"`
+	SourceSHA        *string           `json:"source_sha,omitempty"`
 	TargetRepoID     *int64            `json:"target_repo_id,omitempty"`
 	TargetBranch     *string           `json:"target_branch,omitempty"`
-	MergeMethod      *string           `json:"merge_method,omitempty"`
+	MergeMethod      *MergeMethod      `json:"merge_method,omitempty"`
 	MergeCheckStatus *string           `json:"merge_check_status,omitempty"`
 	MergeSHA         *string           `json:"merge_sha,omitempty"`
 	MergedBy         *int64            `json:"merged_by,omitempty"`
@@ -66,16 +69,26 @@ type PrincipalInfo struct {
 
 // Label represents a repository label
 type Label struct {
-	ID    *int64  `json:"id,omitempty"`
-	Key   *string `json:"key,omitempty"`
-	Value *string `json:"value,omitempty"`
-	Color *string `json:"color,omitempty"`
-	Scope *string `json:"scope,omitempty"`
+	ID          *int64  `json:"id,omitempty"`
+	Key         *string `json:"key,omitempty"`
+	Value       *string `json:"value,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	RepoID      *int64  `json:"repo_id,omitempty"`
+	SpaceID     *int64  `json:"space_id,omitempty"`
+	// Scope is 0 for repository labels and the depth of the defining space for inherited labels
+	Scope *int64 `json:"scope,omitempty"`
+}
+
+// IsInherited reports whether the label is defined by a parent space rather than the repository
+func (l *Label) IsInherited() bool {
+	return l.Scope != nil && *l.Scope > 0
 }
 
 // Reviewer represents a pull request reviewer
 type Reviewer struct {
-	Principal      *PrincipalInfo `json:"principal,omitempty"`
+	Principal      *PrincipalInfo `json:"reviewer,omitempty"`
 	Type           *string        `json:"type,omitempty"`
 	ReviewDecision *string        `json:"review_decision,omitempty"`
 	SHA            *string        `json:"sha,omitempty"`
@@ -100,7 +113,8 @@ type UpdatePullRequestOptions struct {
 
 // StatePullRequestOptions specifies options for changing pull request state
 type StatePullRequestOptions struct {
-	State *string `json:"state,omitempty"`
+	State   *string `json:"state,omitempty"`
+	IsDraft *bool   `json:"is_draft,omitempty"`
 }
 
 // ListPullRequestsOptions specifies options for listing pull requests
@@ -114,12 +128,13 @@ type ListPullRequestsOptions struct {
 
 // MergePullRequestOptions specifies options for merging a pull request
 type MergePullRequestOptions struct {
-	Method        *string `json:"method,omitempty"`
-	CommitMessage *string `json:"commit_message,omitempty"`
-	SourceSHA     *string `json:"source_sha,omitempty"`
-	BypassRules   *bool   `json:"bypass_rules,omitempty"`
-	DryRun        *bool   `json:"dry_run,omitempty"`
-	DryRunRules   *bool   `json:"dry_run_rules,omitempty"`
+	Method             *MergeMethod `json:"method,omitempty"`
+	CommitMessage      *string      `json:"commit_message,omitempty"`
+	SourceSHA          *string      `json:"source_sha,omitempty"`
+	BypassRules        *bool        `json:"bypass_rules,omitempty"`
+	DryRun             *bool        `json:"dry_run,omitempty"`
+	DryRunRules        *bool        `json:"dry_run_rules,omitempty"`
+	DeleteSourceBranch *bool        `json:"delete_source_branch,omitempty"`
 }
 
 // PullReqActivitySuggestionsMetadata contains metadata for code comment suggestions
@@ -163,6 +178,11 @@ type CreatePullRequestCommentOptions struct {
 	ReplyTo *int64  `json:"reply_to,omitempty"`
 }
 
+// UpdatePullRequestCommentOptions specifies options for editing a pull request comment
+type UpdatePullRequestCommentOptions struct {
+	Text *string `json:"text,omitempty"`
+}
+
 // CreatePullRequest creates a new pull request
 func (s *PullRequestsService) CreatePullRequest(ctx context.Context, repoPath string, opt *CreatePullRequestOptions) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/pullreq", url.PathEscape(repoPath))
@@ -176,28 +196,28 @@ func (s *PullRequestsService) CreatePullRequest(ctx context.Context, repoPath st
 
 // ListPullRequests lists pull requests for a repository
 func (s *PullRequestsService) ListPullRequests(ctx context.Context, repoPath string, opt *ListPullRequestsOptions) ([]*PullRequest, *Response, error) {
+	if opt == nil {
+		opt = &ListPullRequestsOptions{}
+	}
+
 	path := fmt.Sprintf("repos/%s/pullreq", url.PathEscape(repoPath))
 	fullURL := s.client.buildFullURL(path)
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx)
 
-	// Add query parameters if options provided
-	if opt != nil {
-		// Add common query parameters
-		buildQueryParams(req, &opt.ListOptions)
+	s.client.buildQueryParams(req, &opt.ListOptions)
 
-		// Add specific query parameters
-		if opt.State != nil {
-			req.SetQueryParam("state", *opt.State)
-		}
-		if opt.SourceBranch != nil {
-			req.SetQueryParam("source_branch", *opt.SourceBranch)
-		}
-		if opt.TargetBranch != nil {
-			req.SetQueryParam("target_branch", *opt.TargetBranch)
-		}
-		if opt.CreatedBy != nil {
-			req.SetQueryParam("created_by", fmt.Sprintf("%d", *opt.CreatedBy))
-		}
+	// Add specific query parameters
+	if opt.State != nil {
+		req.SetQueryParam("state", *opt.State)
+	}
+	if opt.SourceBranch != nil {
+		req.SetQueryParam("source_branch", *opt.SourceBranch)
+	}
+	if opt.TargetBranch != nil {
+		req.SetQueryParam("target_branch", *opt.TargetBranch)
+	}
+	if opt.CreatedBy != nil {
+		req.SetQueryParam("created_by", fmt.Sprintf("%d", *opt.CreatedBy))
 	}
 
 	var pullRequests []*PullRequest
@@ -219,6 +239,139 @@ func (s *PullRequestsService) ListPullRequests(ctx context.Context, repoPath str
 	return pullRequests, response, nil
 }
 
+// ListPullRequestsPaginated lists pull requests and returns a Page that can fetch the next page
+func (s *PullRequestsService) ListPullRequestsPaginated(ctx context.Context, repoPath string, opt *ListPullRequestsOptions) (*Page[PullRequest], error) {
+	var base ListPullRequestsOptions
+	if opt != nil {
+		base = *opt
+	}
+	return fetchPage(ctx, base.Page, func(ctx context.Context, page int) ([]*PullRequest, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListPullRequests(ctx, repoPath, &o)
+	})
+}
+
+// ListAllPullRequests lists pull requests across all pages, up to all.MaxResults
+func (s *PullRequestsService) ListAllPullRequests(ctx context.Context, repoPath string, opt *ListPullRequestsOptions, all *ListAllOptions) ([]*PullRequest, *Response, error) {
+	var base ListPullRequestsOptions
+	if opt != nil {
+		base = *opt
+	}
+	return listAllMax(ctx, all.maxResults(), func(ctx context.Context, page int) ([]*PullRequest, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListPullRequests(ctx, repoPath, &o)
+	})
+}
+
+// PullRequestSummary holds live pull request counts for a repository.
+// Draft pull requests are open, so Draft is a subset of Open. Draft is nil
+// unless drafts were counted (see PullRequestSummaryOptions.CountDrafts).
+type PullRequestSummary struct {
+	Open   int  `json:"open"`
+	Closed int  `json:"closed"`
+	Merged int  `json:"merged"`
+	Draft  *int `json:"draft,omitempty"`
+}
+
+// PullRequestSummaryOptions specifies options for GetPullRequestSummary
+type PullRequestSummaryOptions struct {
+	// CountDrafts counts draft pull requests. The API cannot filter by draft
+	// state, so this lists every open pull request, one request per 100.
+	CountDrafts *bool
+}
+
+// GetPullRequestSummary returns the number of open, closed and merged pull
+// requests in a repository, and optionally of drafts. Unlike the counters on
+// Repository these are live: each state is counted from the x-total header
+// of a single one-item list request.
+func (s *PullRequestsService) GetPullRequestSummary(ctx context.Context, repoPath string, opt *PullRequestSummaryOptions) (*PullRequestSummary, *Response, error) {
+	summary := &PullRequestSummary{}
+	var (
+		resp *Response
+		err  error
+	)
+	if summary.Open, resp, err = s.countPullRequests(ctx, repoPath, "open"); err != nil {
+		return nil, resp, err
+	}
+	if summary.Closed, resp, err = s.countPullRequests(ctx, repoPath, "closed"); err != nil {
+		return nil, resp, err
+	}
+	if summary.Merged, resp, err = s.countPullRequests(ctx, repoPath, "merged"); err != nil {
+		return nil, resp, err
+	}
+
+	if opt != nil && opt.CountDrafts != nil && *opt.CountDrafts {
+		open, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*PullRequest, *Response, error) {
+			return s.ListPullRequests(ctx, repoPath, &ListPullRequestsOptions{
+				ListOptions: ListOptions{Page: Ptr(page), Limit: Ptr(100)},
+				State:       Ptr("open"),
+			})
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+		drafts := 0
+		for _, pr := range open {
+			if pr.IsDraft != nil && *pr.IsDraft {
+				drafts++
+			}
+		}
+		summary.Draft = &drafts
+		return summary, resp, nil
+	}
+	return summary, resp, nil
+}
+
+// countPullRequests counts pull requests in state from the x-total header
+func (s *PullRequestsService) countPullRequests(ctx context.Context, repoPath, state string) (int, *Response, error) {
+	_, resp, err := s.ListPullRequests(ctx, repoPath, &ListPullRequestsOptions{
+		ListOptions: ListOptions{Limit: Ptr(1)},
+		State:       Ptr(state),
+	})
+	if err != nil {
+		return 0, resp, err
+	}
+	if resp.Total == nil {
+		return 0, resp, fmt.Errorf("count %s pull requests: response has no x-total header", state)
+	}
+	return *resp.Total, resp, nil
+}
+
+// BranchRole selects which side of a pull request a branch is matched against
+type BranchRole string
+
+// BranchRole constants
+const (
+	BranchRoleSource BranchRole = "source"
+	BranchRoleTarget BranchRole = "target"
+)
+
+// ListPullRequestsForBranch lists all open pull requests that use branch as
+// their source or target branch, following pagination. Useful to check for
+// open pull requests before deleting a branch.
+func (s *PullRequestsService) ListPullRequestsForBranch(ctx context.Context, repoPath, branch string, role BranchRole) ([]*PullRequest, *Response, error) {
+	base := ListPullRequestsOptions{
+		ListOptions: ListOptions{Limit: Ptr(100)},
+		State:       Ptr("open"),
+	}
+	switch role {
+	case BranchRoleSource:
+		base.SourceBranch = Ptr(branch)
+	case BranchRoleTarget:
+		base.TargetBranch = Ptr(branch)
+	default:
+		return nil, nil, fmt.Errorf("invalid branch role %q", role)
+	}
+
+	return listAll(ctx, func(ctx context.Context, page int) ([]*PullRequest, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListPullRequests(ctx, repoPath, &o)
+	})
+}
+
 // GetPullRequest retrieves a specific pull request
 func (s *PullRequestsService) GetPullRequest(ctx context.Context, repoPath string, pullRequestNumber int64) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/pullreq/%d", url.PathEscape(repoPath), pullRequestNumber)
@@ -230,6 +383,11 @@ func (s *PullRequestsService) GetPullRequest(ctx context.Context, repoPath strin
 	return &pullRequest, resp, nil
 }
 
+// GetPullRequestOrNil is like GetPullRequest but returns a nil PullRequest without an error when the pull request does not exist
+func (s *PullRequestsService) GetPullRequestOrNil(ctx context.Context, repoPath string, pullRequestNumber int64) (*PullRequest, *Response, error) {
+	return notFoundAsNil(s.GetPullRequest(ctx, repoPath, pullRequestNumber))
+}
+
 // UpdatePullRequest updates a pull request
 func (s *PullRequestsService) UpdatePullRequest(ctx context.Context, repoPath string, pullRequestNumber int64, opt *UpdatePullRequestOptions) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/pullreq/%d", url.PathEscape(repoPath), pullRequestNumber)
@@ -252,6 +410,22 @@ func (s *PullRequestsService) SetPullRequestState(ctx context.Context, repoPath
 	return &pullRequest, resp, nil
 }
 
+// MarkPullRequestDraft converts an open pull request to a draft
+func (s *PullRequestsService) MarkPullRequestDraft(ctx context.Context, repoPath string, pullRequestNumber int64) (*PullRequest, *Response, error) {
+	return s.SetPullRequestState(ctx, repoPath, pullRequestNumber, &StatePullRequestOptions{
+		State:   Ptr("open"),
+		IsDraft: Ptr(true),
+	})
+}
+
+// MarkPullRequestReady marks a draft pull request as ready for review
+func (s *PullRequestsService) MarkPullRequestReady(ctx context.Context, repoPath string, pullRequestNumber int64) (*PullRequest, *Response, error) {
+	return s.SetPullRequestState(ctx, repoPath, pullRequestNumber, &StatePullRequestOptions{
+		State:   Ptr("open"),
+		IsDraft: Ptr(false),
+	})
+}
+
 // MergePullRequest merges a pull request
 func (s *PullRequestsService) MergePullRequest(ctx context.Context, repoPath string, pullRequestNumber int64, opt *MergePullRequestOptions) (*PullRequest, *Response, error) {
 	path := fmt.Sprintf("repos/%s/pullreq/%d/merge", url.PathEscape(repoPath), pullRequestNumber)
@@ -263,6 +437,92 @@ func (s *PullRequestsService) MergePullRequest(ctx context.Context, repoPath str
 	return &pullRequest, resp, nil
 }
 
+// PullRequestSatisfiesRules reports whether the branch rules of a pull
+// request's target branch allow it to be merged. The merge is sent as a dry
+// run, so the server evaluates every merge-blocking rule, such as required
+// approvals, status checks and resolved conversations, and the reasons are
+// returned as Violations. Rules lists the active branch rules,
+// including inherited ones, that match the target branch. Merge conflicts
+// are not rule violations; the server reports them as an error.
+func (s *PullRequestsService) PullRequestSatisfiesRules(ctx context.Context, repoPath string, pullRequestNumber int64) (*RuleCheckResult, *Response, error) {
+	pr, resp, err := s.GetPullRequest(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+	var targetBranch string
+	if pr.TargetBranch != nil {
+		targetBranch = *pr.TargetBranch
+	}
+	rules, resp, err := s.client.Repositories.activeRules(ctx, repoPath, RuleTypeBranch, targetBranch)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	result := &RuleCheckResult{Rules: rules}
+
+	path := fmt.Sprintf("repos/%s/pullreq/%d/merge", url.PathEscape(repoPath), pullRequestNumber)
+	body := &MergePullRequestOptions{SourceSHA: pr.SourceSHA, DryRun: Ptr(true), DryRunRules: Ptr(true)}
+	result.Violations, resp, err = s.client.Repositories.dryRunRules(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, resp, err
+	}
+	result.evaluate()
+	return result, resp, nil
+}
+
+// RevertPullRequestOptions specifies options for reverting a merged pull request
+type RevertPullRequestOptions struct {
+	Title        *string `json:"title,omitempty"`
+	Message      *string `json:"message,omitempty"`
+	RevertBranch *string `json:"revert_branch,omitempty"`
+}
+
+// RevertPullRequestOutput represents the result of reverting a pull request
+type RevertPullRequestOutput struct {
+	Branch *string `json:"branch,omitempty"`
+	Commit *Commit `json:"commit,omitempty"`
+}
+
+// RevertPullRequest creates a branch with a commit reverting a merged pull request
+func (s *PullRequestsService) RevertPullRequest(ctx context.Context, repoPath string, pullRequestNumber int64, opt *RevertPullRequestOptions) (*RevertPullRequestOutput, *Response, error) {
+	path := fmt.Sprintf("repos/%s/pullreq/%d/revert", url.PathEscape(repoPath), pullRequestNumber)
+	var output RevertPullRequestOutput
+	resp, err := s.client.Post(ctx, path, opt, &output)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &output, resp, nil
+}
+
+// UpdateBranchOptions specifies options for updating a pull request's source branch
+type UpdateBranchOptions struct {
+	BypassRules *bool `json:"bypass_rules,omitempty"`
+	DryRun      *bool `json:"dry_run,omitempty"`
+	DryRunRules *bool `json:"dry_run_rules,omitempty"`
+}
+
+// UpdatePullRequestBranch brings the source branch of a pull request up to date by
+// rebasing it onto the target branch. The new source SHA and any rule violations
+// are reported in the output.
+func (s *PullRequestsService) UpdatePullRequestBranch(ctx context.Context, repoPath string, pullRequestNumber int64, opt *UpdateBranchOptions) (*RebaseBranchOutput, *Response, error) {
+	pullRequest, resp, err := s.GetPullRequest(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	rebase := &RebaseBranchOptions{
+		BaseBranch: pullRequest.TargetBranch,
+		HeadBranch: pullRequest.SourceBranch,
+	}
+	if opt != nil {
+		rebase.BypassRules = opt.BypassRules
+		rebase.DryRun = opt.DryRun
+		rebase.DryRunRules = opt.DryRunRules
+	}
+
+	return s.client.Repositories.RebaseBranch(ctx, repoPath, rebase)
+}
+
 // ListPullRequestActivity lists activities/comments for a pull request
 func (s *PullRequestsService) ListPullRequestActivity(ctx context.Context, repoPath string, pullRequestNumber int64, opt *ListOptions) ([]*PullRequestActivity, *Response, error) {
 	path := fmt.Sprintf("repos/%s/pullreq/%d/activities", url.PathEscape(repoPath), pullRequestNumber)
@@ -285,18 +545,55 @@ func (s *PullRequestsService) CreatePullRequestComment(ctx context.Context, repo
 	return &comment, resp, nil
 }
 
+// UpdatePullRequestComment edits the text of a pull request comment
+func (s *PullRequestsService) UpdatePullRequestComment(ctx context.Context, repoPath string, pullRequestNumber, commentID int64, opt *UpdatePullRequestCommentOptions) (*PullRequestActivity, *Response, error) {
+	path := fmt.Sprintf("repos/%s/pullreq/%d/comments/%d", url.PathEscape(repoPath), pullRequestNumber, commentID)
+	var comment PullRequestActivity
+	resp, err := s.client.Patch(ctx, path, opt, &comment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &comment, resp, nil
+}
+
+// DeletePullRequestComment deletes a pull request comment. commentID is the
+// ID of the comment's PullRequestActivity.
+func (s *PullRequestsService) DeletePullRequestComment(ctx context.Context, repoPath string, pullRequestNumber, commentID int64) (*Response, error) {
+	path := fmt.Sprintf("repos/%s/pullreq/%d/comments/%d", url.PathEscape(repoPath), pullRequestNumber, commentID)
+	return s.client.Delete(ctx, path, nil)
+}
+
 // AddPullRequestReviewer adds a reviewer to a pull request
 func (s *PullRequestsService) AddPullRequestReviewer(ctx context.Context, repoPath string, pullRequestNumber int64, reviewerUID string) (*Response, error) {
-	path := fmt.Sprintf("repos/%s/pullreq/%d/reviewers/%s", url.PathEscape(repoPath), pullRequestNumber, reviewerUID)
+	path := fmt.Sprintf("repos/%s/pullreq/%d/reviewers/%s", url.PathEscape(repoPath), pullRequestNumber, url.PathEscape(reviewerUID))
 	resp, err := s.client.Put(ctx, path, nil, nil)
 	return resp, err
 }
 
-// RemovePullRequestReviewer removes a reviewer from a pull request
+// ErrReviewerNotFound is returned when a reviewer UID is not among the reviewers of a pull request
+var ErrReviewerNotFound = errors.New("reviewer not found")
+
+// RemovePullRequestReviewer removes the reviewer with principal UID
+// reviewerUID from a pull request. The API identifies reviewers by principal
+// ID, so the UID is looked up among the current reviewers; use
+// RemovePullRequestReviewerByID when the ID is already known.
 func (s *PullRequestsService) RemovePullRequestReviewer(ctx context.Context, repoPath string, pullRequestNumber int64, reviewerUID string) (*Response, error) {
-	path := fmt.Sprintf("repos/%s/pullreq/%d/reviewers/%s", url.PathEscape(repoPath), pullRequestNumber, reviewerUID)
-	resp, err := s.client.Delete(ctx, path, nil)
-	return resp, err
+	reviewers, resp, err := s.ListPullRequestReviewers(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return resp, err
+	}
+	for _, reviewer := range reviewers {
+		if p := reviewer.Principal; p != nil && p.ID != nil && p.UID != nil && *p.UID == reviewerUID {
+			return s.RemovePullRequestReviewerByID(ctx, repoPath, pullRequestNumber, *p.ID)
+		}
+	}
+	return resp, fmt.Errorf("%w: %q", ErrReviewerNotFound, reviewerUID)
+}
+
+// RemovePullRequestReviewerByID removes the reviewer with principalID from a pull request
+func (s *PullRequestsService) RemovePullRequestReviewerByID(ctx context.Context, repoPath string, pullRequestNumber int64, principalID int64) (*Response, error) {
+	path := fmt.Sprintf("repos/%s/pullreq/%d/reviewers/%d", url.PathEscape(repoPath), pullRequestNumber, principalID)
+	return s.client.Delete(ctx, path, nil)
 }
 
 // ListPullRequestReviewers lists reviewers for a pull request
@@ -336,6 +633,7 @@ const (
 	PullReqReviewDecisionApproved         PullReqReviewDecision = "approved"
 	PullReqReviewDecisionRequestedChanges PullReqReviewDecision = "changereq"
 	PullReqReviewDecisionPending          PullReqReviewDecision = "pending"
+	PullReqReviewDecisionReviewed         PullReqReviewDecision = "reviewed"
 )
 
 // CombinedReviewers represents combined individual and user group reviewers
@@ -360,6 +658,96 @@ func (s *PullRequestsService) ListPullRequestCombinedReviewers(ctx context.Conte
 	return &combinedReviewers, resp, nil
 }
 
+// ReviewSummary tallies the review decisions on a pull request
+type ReviewSummary struct {
+	Approved         int
+	ChangesRequested int
+	// Pending counts reviewers who have not reviewed yet or only commented
+	// (PullReqReviewDecisionReviewed)
+	Pending int
+	// StaleApprovals are approvals of an older commit that do not count
+	// because a branch rule requires approval of the latest commit
+	StaleApprovals int
+
+	// RequiredApprovals is the highest minimum approval count among active
+	// branch rules that apply to the target branch
+	RequiredApprovals int
+	// NoChangeRequestRequired is set when an active branch rule blocks merging
+	// while changes are requested
+	NoChangeRequestRequired bool
+	// ApprovalsMet reports whether the approval requirements above are satisfied
+	ApprovalsMet bool
+}
+
+// GetPullRequestReviewSummary counts the review decisions of a pull request's
+// reviewers and checks them against the approval requirements of the active
+// branch rules, including inherited ones, that apply to its target branch.
+// User group decisions are derived from their members' reviews, so only
+// individual reviewers are counted. Code owner and default reviewer
+// requirements are not evaluated.
+func (s *PullRequestsService) GetPullRequestReviewSummary(ctx context.Context, repoPath string, pullRequestNumber int64) (*ReviewSummary, *Response, error) {
+	pr, resp, err := s.GetPullRequest(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+	var targetBranch, sourceSHA string
+	if pr.TargetBranch != nil {
+		targetBranch = *pr.TargetBranch
+	}
+	if pr.SourceSHA != nil {
+		sourceSHA = *pr.SourceSHA
+	}
+	rules, resp, err := s.client.Repositories.activeRules(ctx, repoPath, RuleTypeBranch, targetBranch)
+	if err != nil {
+		return nil, resp, err
+	}
+	reviewers, resp, err := s.ListPullRequestCombinedReviewers(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	summary := &ReviewSummary{}
+	requireLatestCommit := false
+	for _, rule := range rules {
+		if rule.Definition == nil || rule.Definition.PullReq == nil || rule.Definition.PullReq.Approvals == nil {
+			continue
+		}
+		approvals := rule.Definition.PullReq.Approvals
+		if approvals.RequireMinimumCount != nil && *approvals.RequireMinimumCount > summary.RequiredApprovals {
+			summary.RequiredApprovals = *approvals.RequireMinimumCount
+		}
+		if approvals.RequireLatestCommit != nil && *approvals.RequireLatestCommit {
+			requireLatestCommit = true
+		}
+		if approvals.RequireNoChangeRequest != nil && *approvals.RequireNoChangeRequest {
+			summary.NoChangeRequestRequired = true
+		}
+	}
+
+	for _, reviewer := range reviewers.Reviewers {
+		var decision PullReqReviewDecision
+		if reviewer.ReviewDecision != nil {
+			decision = PullReqReviewDecision(*reviewer.ReviewDecision)
+		}
+		switch decision {
+		case PullReqReviewDecisionApproved:
+			if requireLatestCommit && (reviewer.SHA == nil || *reviewer.SHA != sourceSHA) {
+				summary.StaleApprovals++
+			} else {
+				summary.Approved++
+			}
+		case PullReqReviewDecisionRequestedChanges:
+			summary.ChangesRequested++
+		default:
+			summary.Pending++
+		}
+	}
+
+	summary.ApprovalsMet = summary.Approved >= summary.RequiredApprovals &&
+		!(summary.NoChangeRequestRequired && summary.ChangesRequested > 0)
+	return summary, resp, nil
+}
+
 // AddPullRequestUserGroupReviewer adds a user group reviewer to a pull request
 func (s *PullRequestsService) AddPullRequestUserGroupReviewer(ctx context.Context, repoPath string, pullRequestNumber int64, userGroupID int64) (*UserGroupReviewer, *Response, error) {
 	path := fmt.Sprintf("repos/%s/pullreq/%d/reviewers/usergroups", url.PathEscape(repoPath), pullRequestNumber)
@@ -381,3 +769,126 @@ func (s *PullRequestsService) RemovePullRequestUserGroupReviewer(ctx context.Con
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
+
+// ReviewerAddRequest represents a request to add an individual reviewer
+type ReviewerAddRequest struct {
+	ReviewerID *int64 `json:"reviewer_id,omitempty"`
+}
+
+// AddPullRequestReviewerByID adds a reviewer to a pull request by principal ID
+func (s *PullRequestsService) AddPullRequestReviewerByID(ctx context.Context, repoPath string, pullRequestNumber int64, reviewerID int64) (*Response, error) {
+	path := fmt.Sprintf("repos/%s/pullreq/%d/reviewers", url.PathEscape(repoPath), pullRequestNumber)
+	req := &ReviewerAddRequest{
+		ReviewerID: &reviewerID,
+	}
+
+	resp, err := s.client.Put(ctx, path, req, nil)
+	return resp, err
+}
+
+// OwnerEvaluation represents the review state of a single code owner
+type OwnerEvaluation struct {
+	Owner          *PrincipalInfo         `json:"owner,omitempty"`
+	ReviewDecision *PullReqReviewDecision `json:"review_decision,omitempty"`
+	ReviewSHA      *string                `json:"review_sha,omitempty"`
+}
+
+// UserGroupOwnerEvaluation represents the review state of a code owner user group
+type UserGroupOwnerEvaluation struct {
+	ID          *string            `json:"id,omitempty"`
+	Name        *string            `json:"name,omitempty"`
+	Evaluations []*OwnerEvaluation `json:"evaluations,omitempty"`
+}
+
+// CodeOwnerEvaluationEntry represents a CODEOWNERS rule matching the pull request
+type CodeOwnerEvaluationEntry struct {
+	LineNumber                *int64                      `json:"line_number,omitempty"`
+	Pattern                   *string                     `json:"pattern,omitempty"`
+	OwnerEvaluations          []*OwnerEvaluation          `json:"owner_evaluations,omitempty"`
+	UserGroupOwnerEvaluations []*UserGroupOwnerEvaluation `json:"user_group_owner_evaluations,omitempty"`
+}
+
+// CodeOwnerEvaluation represents the code owners evaluation of a pull request
+type CodeOwnerEvaluation struct {
+	EvaluationEntries []*CodeOwnerEvaluationEntry `json:"evaluation_entries,omitempty"`
+	FileSHA           *string                     `json:"file_sha,omitempty"`
+}
+
+// GetPullRequestCodeOwners evaluates the code owners of the files changed by a pull request
+func (s *PullRequestsService) GetPullRequestCodeOwners(ctx context.Context, repoPath string, pullRequestNumber int64) (*CodeOwnerEvaluation, *Response, error) {
+	path := fmt.Sprintf("repos/%s/pullreq/%d/codeowners", url.PathEscape(repoPath), pullRequestNumber)
+	var evaluation CodeOwnerEvaluation
+	resp, err := s.client.Get(ctx, path, &evaluation)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &evaluation, resp, nil
+}
+
+// ErrUnsupportedOwner is returned when a code owner cannot be requested as a reviewer
+var ErrUnsupportedOwner = errors.New("unsupported code owner")
+
+// RequestPullRequestReviewFromRules requests reviews from the code owners of the files
+// changed by a pull request and returns the resulting reviewers. The pull request
+// author is never requested.
+//
+// User group owners are expanded to the members the evaluation lists, since
+// the evaluation carries the group identifier rather than the numeric ID the
+// user group reviewer endpoint needs. A group listed without members cannot be
+// requested; the reviewers are still returned, together with an error wrapping
+// ErrUnsupportedOwner for each such group.
+func (s *PullRequestsService) RequestPullRequestReviewFromRules(ctx context.Context, repoPath string, pullRequestNumber int64) (*CombinedReviewers, *Response, error) {
+	pullRequest, resp, err := s.GetPullRequest(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	evaluation, resp, err := s.GetPullRequestCodeOwners(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	requested := make(map[int64]bool)
+	if pullRequest.CreatedBy != nil {
+		requested[*pullRequest.CreatedBy] = true
+	}
+	request := func(owners []*OwnerEvaluation) (*Response, error) {
+		for _, owner := range owners {
+			if owner.Owner == nil || owner.Owner.ID == nil || requested[*owner.Owner.ID] {
+				continue
+			}
+			requested[*owner.Owner.ID] = true
+
+			if resp, err := s.AddPullRequestReviewerByID(ctx, repoPath, pullRequestNumber, *owner.Owner.ID); err != nil {
+				return resp, err
+			}
+		}
+		return nil, nil
+	}
+
+	var unsupported []error
+	for _, entry := range evaluation.EvaluationEntries {
+		if resp, err := request(entry.OwnerEvaluations); err != nil {
+			return nil, resp, err
+		}
+		for _, group := range entry.UserGroupOwnerEvaluations {
+			if len(group.Evaluations) == 0 {
+				name := ""
+				if group.ID != nil {
+					name = *group.ID
+				}
+				unsupported = append(unsupported, fmt.Errorf("%w: user group %q has no listed members", ErrUnsupportedOwner, name))
+				continue
+			}
+			if resp, err := request(group.Evaluations); err != nil {
+				return nil, resp, err
+			}
+		}
+	}
+
+	reviewers, resp, err := s.ListPullRequestCombinedReviewers(ctx, repoPath, pullRequestNumber)
+	if err != nil {
+		return nil, resp, err
+	}
+	return reviewers, resp, errors.Join(unsupported...)
+}
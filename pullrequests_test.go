@@ -0,0 +1,453 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// TestGetPullRequestSummary tests that counts come from x-total headers and drafts are only counted on request
+func TestGetPullRequestSummary(t *testing.T) {
+	var listed []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		listed = append(listed, q.Get("state")+"/"+q.Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		total := map[string]string{"open": "3", "closed": "7", "merged": "2"}[q.Get("state")]
+		w.Header().Set("x-total", total)
+		if q.Get("limit") == "1" {
+			json.NewEncoder(w).Encode([]*PullRequest{{Number: Ptr(int64(1))}})
+			return
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+		if page == 1 {
+			w.Header().Set("x-next-page", "2")
+			json.NewEncoder(w).Encode([]*PullRequest{{Number: Ptr(int64(1)), IsDraft: Ptr(true)}, {Number: Ptr(int64(2))}})
+			return
+		}
+		json.NewEncoder(w).Encode([]*PullRequest{{Number: Ptr(int64(3)), IsDraft: Ptr(true)}})
+	})
+
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	summary, _, err := client.PullRequests.GetPullRequestSummary(ctx, "space/repo", nil)
+	if err != nil {
+		t.Fatalf("GetPullRequestSummary returned error: %v", err)
+	}
+	if summary.Open != 3 || summary.Closed != 7 || summary.Merged != 2 || summary.Draft != nil {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+	if want := []string{"open/1", "closed/1", "merged/1"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("Expected requests %v, got %v", want, listed)
+	}
+
+	summary, _, err = client.PullRequests.GetPullRequestSummary(ctx, "space/repo", &PullRequestSummaryOptions{CountDrafts: Ptr(true)})
+	if err != nil {
+		t.Fatalf("GetPullRequestSummary returned error: %v", err)
+	}
+	if summary.Draft == nil || *summary.Draft != 2 {
+		t.Errorf("Expected 2 drafts, got %v", summary.Draft)
+	}
+}
+
+// TestRemovePullRequestReviewer tests removal by ID and by UID resolved from the reviewer list
+func TestRemovePullRequestReviewer(t *testing.T) {
+	var deleted []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}/reviewers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"reviewer": {"id": 12, "uid": "alice"}, "review_decision": "pending"},
+			{"reviewer": {"id": 13, "uid": "1001"}, "review_decision": "pending"}]`))
+	})
+	mux.HandleFunc("DELETE /api/v1/repos/{repo}/pullreq/{number}/reviewers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	reviewers, _, err := client.PullRequests.ListPullRequestReviewers(ctx, "space/repo", 3)
+	if err != nil {
+		t.Fatalf("ListPullRequestReviewers returned error: %v", err)
+	}
+	if len(reviewers) != 2 || reviewers[0].Principal == nil || *reviewers[0].Principal.ID != 12 {
+		t.Fatalf("Expected reviewer principal to be decoded, got %+v", reviewers)
+	}
+
+	if _, err := client.PullRequests.RemovePullRequestReviewerByID(ctx, "space/repo", 3, 7); err != nil {
+		t.Fatalf("RemovePullRequestReviewerByID returned error: %v", err)
+	}
+	if _, err := client.PullRequests.RemovePullRequestReviewer(ctx, "space/repo", 3, "alice"); err != nil {
+		t.Fatalf("RemovePullRequestReviewer returned error: %v", err)
+	}
+	if _, err := client.PullRequests.RemovePullRequestReviewer(ctx, "space/repo", 3, "1001"); err != nil {
+		t.Fatalf("RemovePullRequestReviewer with a numeric UID returned error: %v", err)
+	}
+	if _, err := client.PullRequests.RemovePullRequestReviewer(ctx, "space/repo", 3, "bob"); !errors.Is(err, ErrReviewerNotFound) {
+		t.Errorf("Expected ErrReviewerNotFound, got %v", err)
+	}
+	if _, err := client.PullRequests.RemovePullRequestReviewer(ctx, "space/repo", 3, "12"); !errors.Is(err, ErrReviewerNotFound) {
+		t.Errorf("Expected a principal ID not to be taken as a UID, got %v", err)
+	}
+
+	if len(deleted) != 3 || deleted[0] != "7" || deleted[1] != "12" || deleted[2] != "13" {
+		t.Errorf("Expected reviewers 7, 12 and 13 to be removed, got %v", deleted)
+	}
+}
+
+// TestListPullRequestsForBranch tests filtering open pull requests by source or target branch
+func TestListPullRequestsForBranch(t *testing.T) {
+	var queries []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("state")+" "+r.URL.Query().Get("source_branch")+" "+r.URL.Query().Get("target_branch"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*PullRequest{{Number: Ptr(int64(1))}})
+	})
+
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	prs, _, err := client.PullRequests.ListPullRequestsForBranch(ctx, "space/repo", "feature", BranchRoleSource)
+	if err != nil {
+		t.Fatalf("ListPullRequestsForBranch returned error: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Errorf("Expected 1 pull request, got %d", len(prs))
+	}
+	if _, _, err := client.PullRequests.ListPullRequestsForBranch(ctx, "space/repo", "main", BranchRoleTarget); err != nil {
+		t.Fatalf("ListPullRequestsForBranch returned error: %v", err)
+	}
+	if _, _, err := client.PullRequests.ListPullRequestsForBranch(ctx, "space/repo", "main", "both"); err == nil {
+		t.Error("Expected error for an invalid role")
+	}
+
+	want := []string{"open feature ", "open  main"}
+	if len(queries) != 2 || queries[0] != want[0] || queries[1] != want[1] {
+		t.Errorf("Expected queries %q, got %q", want, queries)
+	}
+}
+
+func TestGetPullRequestReviewSummary(t *testing.T) {
+	changeReq := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"target_branch":"main","source_sha":"abc"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"default_branch":"main"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/rules", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("inherited") != "true" || r.URL.Query().Get("type") != "branch" {
+			t.Errorf("unexpected rules query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"state":"active","pattern":{"default":true},"definition":{"pullreq":{"approvals":{"require_minimum_count":2,"require_latest_commit":true,"require_no_change_request":true}}}},
+			{"state":"disabled","pattern":{"default":true},"definition":{"pullreq":{"approvals":{"require_minimum_count":5}}}},
+			{"state":"active","pattern":{"include":["release/*"]},"definition":{"pullreq":{"approvals":{"require_minimum_count":4}}}}
+		]`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}/reviewers/combined", func(w http.ResponseWriter, r *http.Request) {
+		decision := "pending"
+		if changeReq {
+			decision = "changereq"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"reviewers":[
+			{"review_decision":"approved","sha":"abc"},
+			{"review_decision":"approved","sha":"old"},
+			{"review_decision":"approved","sha":"abc"},
+			{"review_decision":"reviewed","sha":"abc"},
+			{"review_decision":"` + decision + `"}
+		]}`))
+	})
+	client := NewTestClient(mux)
+
+	summary, _, err := client.PullRequests.GetPullRequestReviewSummary(context.Background(), "space/repo", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequestReviewSummary returned error: %v", err)
+	}
+	want := ReviewSummary{Approved: 2, Pending: 2, StaleApprovals: 1, RequiredApprovals: 2, NoChangeRequestRequired: true, ApprovalsMet: true}
+	if *summary != want {
+		t.Errorf("summary = %+v, want %+v", *summary, want)
+	}
+
+	changeReq = true
+	summary, _, err = client.PullRequests.GetPullRequestReviewSummary(context.Background(), "space/repo", 7)
+	if err != nil {
+		t.Fatalf("GetPullRequestReviewSummary returned error: %v", err)
+	}
+	if summary.ChangesRequested != 1 || summary.ApprovalsMet {
+		t.Errorf("with change request: summary = %+v, want 1 change request and approvals not met", *summary)
+	}
+}
+
+func TestUpdateAndDeletePullRequestComment(t *testing.T) {
+	deleted := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /api/v1/repos/space%2Frepo/pullreq/7/comments/42", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["text"] != "edited" {
+			t.Errorf("text = %v, want edited", body["text"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":42,"text":"edited"}`))
+	})
+	mux.HandleFunc("DELETE /api/v1/repos/space%2Frepo/pullreq/7/comments/42", func(w http.ResponseWriter, r *http.Request) {
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := NewTestClient(mux)
+
+	comment, _, err := client.PullRequests.UpdatePullRequestComment(context.Background(), "space/repo", 7, 42, &UpdatePullRequestCommentOptions{Text: Ptr("edited")})
+	if err != nil {
+		t.Fatalf("UpdatePullRequestComment returned error: %v", err)
+	}
+	if comment.ID == nil || *comment.ID != 42 || comment.Text == nil || *comment.Text != "edited" {
+		t.Errorf("comment = %+v", comment)
+	}
+
+	if _, err := client.PullRequests.DeletePullRequestComment(context.Background(), "space/repo", 7, 42); err != nil {
+		t.Fatalf("DeletePullRequestComment returned error: %v", err)
+	}
+	if !deleted {
+		t.Error("DELETE was not sent")
+	}
+}
+
+func TestPullRequestSatisfiesRules(t *testing.T) {
+	var mergeBody map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"target_branch":"main","source_sha":"abc"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"default_branch":"main"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"identifier":"protect-main","state":"active","pattern":{"default":true}},
+			{"identifier":"releases","state":"active","pattern":{"include":["release/*"]}}
+		]`))
+	})
+	mux.HandleFunc("POST /api/v1/repos/{repo}/pullreq/{number}/merge", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&mergeBody); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"dry_run":true,"dry_run_rules":true,"rule_violations":[
+			{"rule":{"identifier":"protect-main"},"bypassable":true,"violations":[
+				{"code":"pullreq.approvals.require_minimum_count","message":"Insufficient number of approvals"},
+				{"code":"pullreq.comments.require_resolve_all","message":"All comments must be resolved"}
+			]}
+		]}`))
+	})
+	client := NewTestClient(mux)
+
+	result, _, err := client.PullRequests.PullRequestSatisfiesRules(context.Background(), "space/repo", 7)
+	if err != nil {
+		t.Fatalf("PullRequestSatisfiesRules returned error: %v", err)
+	}
+	if mergeBody["dry_run"] != true || mergeBody["dry_run_rules"] != true || mergeBody["source_sha"] != "abc" {
+		t.Errorf("merge body = %v, want a dry run of source_sha abc", mergeBody)
+	}
+	if !result.Blocked || !result.Bypassable {
+		t.Errorf("Expected a bypassable block, got %+v", result)
+	}
+	if len(result.Rules) != 1 || *result.Rules[0].Identifier != "protect-main" {
+		t.Errorf("Expected only protect-main to apply, got %d rules", len(result.Rules))
+	}
+	if len(result.Violations) != 1 || len(result.Violations[0].Violations) != 2 {
+		t.Errorf("Unexpected violations %+v", result.Violations)
+	}
+}
+
+// TestRequestPullRequestReviewFromRules tests that user and group code owners are requested and memberless groups are reported
+func TestRequestPullRequestReviewFromRules(t *testing.T) {
+	var added []int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"created_by":1}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}/codeowners", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"evaluation_entries":[{"pattern":"*","owner_evaluations":[{"owner":{"id":1}},{"owner":{"id":2}}],` +
+			`"user_group_owner_evaluations":[{"id":"devs","evaluations":[{"owner":{"id":2}},{"owner":{"id":3}}]},{"id":"ops"}]}]}`))
+	})
+	mux.HandleFunc("PUT /api/v1/repos/{repo}/pullreq/{number}/reviewers", func(w http.ResponseWriter, r *http.Request) {
+		var body ReviewerAddRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ReviewerID == nil {
+			t.Errorf("Unexpected reviewer request: %v", err)
+			return
+		}
+		added = append(added, *body.ReviewerID)
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}/reviewers/combined", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"reviewers":[{"reviewer":{"id":2}},{"reviewer":{"id":3}}]}`))
+	})
+	client := NewTestClient(mux)
+
+	reviewers, _, err := client.PullRequests.RequestPullRequestReviewFromRules(context.Background(), "space/repo", 7)
+	if !errors.Is(err, ErrUnsupportedOwner) {
+		t.Errorf("Expected ErrUnsupportedOwner for the memberless group, got %v", err)
+	}
+	if reviewers == nil || len(reviewers.Reviewers) != 2 {
+		t.Errorf("Expected the combined reviewers to be returned, got %+v", reviewers)
+	}
+	if !reflect.DeepEqual(added, []int64{2, 3}) {
+		t.Errorf("Expected reviewers [2 3] to be added, got %v", added)
+	}
+}
+
+// TestMarkPullRequestDraftAndReady tests that draft toggling posts is_draft to the state endpoint
+func TestMarkPullRequestDraftAndReady(t *testing.T) {
+	var bodies []StatePullRequestOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/repos/{repo}/pullreq/{number}/state", func(w http.ResponseWriter, r *http.Request) {
+		var body StatePullRequestOptions
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"state":"open"}`))
+	})
+	client := NewTestClient(mux)
+
+	if _, _, err := client.PullRequests.MarkPullRequestDraft(context.Background(), "space/repo", 7); err != nil {
+		t.Fatalf("MarkPullRequestDraft returned error: %v", err)
+	}
+	if _, _, err := client.PullRequests.MarkPullRequestReady(context.Background(), "space/repo", 7); err != nil {
+		t.Fatalf("MarkPullRequestReady returned error: %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("Expected 2 state requests, got %d", len(bodies))
+	}
+	for i, want := range []bool{true, false} {
+		if bodies[i].State == nil || *bodies[i].State != "open" || bodies[i].IsDraft == nil || *bodies[i].IsDraft != want {
+			t.Errorf("Request %d: expected state open and is_draft %v, got %+v", i, want, bodies[i])
+		}
+	}
+}
+
+// TestMergePullRequestDeleteSourceBranch tests that delete_source_branch is sent only when set
+func TestMergePullRequestDeleteSourceBranch(t *testing.T) {
+	var bodies []map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/repos/{repo}/pullreq/{number}/merge", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"state":"merged"}`))
+	})
+	client := NewTestClient(mux)
+
+	for _, opt := range []*MergePullRequestOptions{
+		{Method: Ptr(MergeMethodSquash), DeleteSourceBranch: Ptr(true)},
+		{Method: Ptr(MergeMethodSquash)},
+	} {
+		if _, _, err := client.PullRequests.MergePullRequest(context.Background(), "space/repo", 7, opt); err != nil {
+			t.Fatalf("MergePullRequest returned error: %v", err)
+		}
+	}
+
+	if bodies[0]["delete_source_branch"] != true {
+		t.Errorf("Expected delete_source_branch true, got %v", bodies[0]["delete_source_branch"])
+	}
+	if _, ok := bodies[1]["delete_source_branch"]; ok {
+		t.Errorf("Expected delete_source_branch to be omitted, got %v", bodies[1])
+	}
+}
+
+// TestRevertPullRequest tests that the revert options are posted and the revert branch is decoded
+func TestRevertPullRequest(t *testing.T) {
+	var body RevertPullRequestOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/repos/{repo}/pullreq/{number}/revert", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("number") != "7" {
+			t.Errorf("Unexpected pull request number %q", r.PathValue("number"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"branch":"revert-pullreq-7","commit":{"sha":"abc123"}}`))
+	})
+	client := NewTestClient(mux)
+
+	output, _, err := client.PullRequests.RevertPullRequest(context.Background(), "space/repo", 7, &RevertPullRequestOptions{
+		Title:        Ptr("Revert #7"),
+		RevertBranch: Ptr("revert-pullreq-7"),
+	})
+	if err != nil {
+		t.Fatalf("RevertPullRequest returned error: %v", err)
+	}
+
+	if body.Title == nil || *body.Title != "Revert #7" || body.RevertBranch == nil || *body.RevertBranch != "revert-pullreq-7" {
+		t.Errorf("Unexpected revert request: %+v", body)
+	}
+	if output.Branch == nil || *output.Branch != "revert-pullreq-7" || output.Commit == nil || output.Commit.SHA == nil || *output.Commit.SHA != "abc123" {
+		t.Errorf("Unexpected revert output: %+v", output)
+	}
+}
+
+// TestUpdatePullRequestBranch tests that the source branch is rebased onto the target branch
+func TestUpdatePullRequestBranch(t *testing.T) {
+	var body RebaseBranchOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}/pullreq/{number}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number":7,"source_branch":"feature","target_branch":"main"}`))
+	})
+	mux.HandleFunc("POST /api/v1/repos/{repo}/rebase", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"new_head_branch_sha":"def456"}`))
+	})
+	client := NewTestClient(mux)
+
+	output, _, err := client.PullRequests.UpdatePullRequestBranch(context.Background(), "space/repo", 7, &UpdateBranchOptions{DryRun: Ptr(true)})
+	if err != nil {
+		t.Fatalf("UpdatePullRequestBranch returned error: %v", err)
+	}
+
+	if body.BaseBranch == nil || *body.BaseBranch != "main" || body.HeadBranch == nil || *body.HeadBranch != "feature" {
+		t.Errorf("Expected feature to be rebased onto main, got %+v", body)
+	}
+	if body.DryRun == nil || !*body.DryRun {
+		t.Errorf("Expected dry_run to be passed through, got %+v", body)
+	}
+	if output.NewHeadBranchSHA == nil || *output.NewHeadBranchSHA != "def456" {
+		t.Errorf("Unexpected rebase output: %+v", output)
+	}
+}
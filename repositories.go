@@ -7,9 +7,19 @@
 package gitness
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // RepositoriesService handles communication with repository related methods
@@ -29,8 +39,10 @@ type Repository struct {
 	Created        *Time   `json:"created,omitempty"`
 	Updated        *Time   `json:"updated,omitempty"`
 	Size           *int64  `json:"size,omitempty"`
+	SizeLFS        *int64  `json:"size_lfs,omitempty"`
 	SizeUpdated    *Time   `json:"size_updated,omitempty"`
 	GitURL         *string `json:"git_url,omitempty"`
+	GitSSHURL      *string `json:"git_ssh_url,omitempty"`
 	DefaultBranch  *string `json:"default_branch,omitempty"`
 	ForkID         *int64  `json:"fork_id,omitempty"`
 	NumForks       *int    `json:"num_forks,omitempty"`
@@ -41,11 +53,70 @@ type Repository struct {
 	Importing      *bool   `json:"importing,omitempty"`
 }
 
+// CloneScheme selects the protocol of a clone URL
+type CloneScheme string
+
+// CloneScheme constants
+const (
+	CloneSchemeHTTP CloneScheme = "http"
+	CloneSchemeSSH  CloneScheme = "ssh"
+)
+
+// cloneUsername is the user name sent with the token in authenticated clone URLs
+const cloneUsername = "git"
+
+// CloneURL returns the URL to clone the repository with scheme, or "" if
+// the server did not report one. CloneSchemeHTTP covers both http and https.
+func (r *Repository) CloneURL(scheme CloneScheme) string {
+	if r == nil {
+		return ""
+	}
+	var u *string
+	switch scheme {
+	case CloneSchemeHTTP:
+		u = r.GitURL
+	case CloneSchemeSSH:
+		u = r.GitSSHURL
+	}
+	if u == nil {
+		return ""
+	}
+	return *u
+}
+
+// AuthenticatedCloneURL returns the HTTP clone URL of repo with the client's
+// token embedded as password, ready to pass to git clone in CI. The result
+// contains a credential and must not be logged.
+func (c *Client) AuthenticatedCloneURL(repo *Repository) (string, error) {
+	if c.token == "" {
+		return "", errors.New("authenticated clone URL requires a token")
+	}
+	raw := repo.CloneURL(CloneSchemeHTTP)
+	if raw == "" {
+		return "", errors.New("repository has no HTTP clone URL")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid clone URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("clone URL scheme %q is not http or https", u.Scheme)
+	}
+	u.User = url.UserPassword(cloneUsername, c.token)
+	return u.String(), nil
+}
+
 // Branch represents a repository branch
 type Branch struct {
-	Name   *string    `json:"name,omitempty"`
-	SHA    *string    `json:"sha,omitempty"`
-	Commit *CommitSHA `json:"commit,omitempty"`
+	Name      *string     `json:"name,omitempty"`
+	SHA       *string     `json:"sha,omitempty"`
+	Commit    *CommitSHA  `json:"commit,omitempty"`
+	IsDefault *bool       `json:"is_default,omitempty"`
+	Rules     []*RuleInfo `json:"rules,omitempty"`
+
+	// IsProtected reports whether an active rule applies to the branch. It is
+	// derived from Rules and only set when branches are listed with IncludeRules.
+	IsProtected *bool `json:"-"`
 }
 
 // CommitSHA represents basic commit information
@@ -79,13 +150,21 @@ type CreateRepositoryOptions struct {
 	Readme        *bool   `json:"readme,omitempty"`
 }
 
-// UpdateRepositoryOptions specifies options for updating a repository
+// UpdateRepositoryOptions specifies options for updating a repository.
+// Nil fields are left unchanged; a pointer to a zero value is sent as is,
+// so Description: Ptr("") clears the description.
 type UpdateRepositoryOptions struct {
 	Description   *string `json:"description,omitempty"`
 	IsPublic      *bool   `json:"is_public,omitempty"`
 	DefaultBranch *string `json:"default_branch,omitempty"`
+
+	// ValidateDefaultBranch checks that DefaultBranch exists before anything is changed
+	ValidateDefaultBranch *bool `json:"-"`
 }
 
+// ErrBranchNotFound is returned when a referenced branch does not exist
+var ErrBranchNotFound = errors.New("branch not found")
+
 // ImportRepositoryOptions specifies options for importing a repository
 type ImportRepositoryOptions struct {
 	CloneURL   *string `json:"clone_url,omitempty"`
@@ -125,6 +204,11 @@ func (s *RepositoriesService) GetRepository(ctx context.Context, repoPath string
 	return &repository, resp, nil
 }
 
+// GetRepositoryOrNil is like GetRepository but returns a nil Repository without an error when the repository does not exist
+func (s *RepositoriesService) GetRepositoryOrNil(ctx context.Context, repoPath string) (*Repository, *Response, error) {
+	return notFoundAsNil(s.GetRepository(ctx, repoPath))
+}
+
 // CreateRepository creates a new repository
 func (s *RepositoriesService) CreateRepository(ctx context.Context, spaceRef string, opt *CreateRepositoryOptions) (*Repository, *Response, error) {
 	path := fmt.Sprintf("spaces/%s/repos", url.PathEscape(spaceRef))
@@ -136,11 +220,174 @@ func (s *RepositoriesService) CreateRepository(ctx context.Context, spaceRef str
 	return &repository, resp, nil
 }
 
-// UpdateRepository updates a repository
+// CreateRepositoryFromTemplateOptions specifies options for creating a
+// repository from a template repository
+type CreateRepositoryFromTemplateOptions struct {
+	Identifier  *string
+	Description *string
+	IsPublic    *bool
+
+	// TemplateRef is the path of the template repository, e.g. "space/golden"
+	TemplateRef *string
+
+	// DefaultBranch of the new repository; defaults to the template's default branch
+	DefaultBranch *string
+
+	// Message of the initial commit; defaults to "Create from template <ref>"
+	Message *string
+}
+
+// CreateRepositoryFromTemplate creates a repository in spaceRef pre-populated
+// with the files on the default branch of a template repository.
+//
+// Gitness has no server-side template support, so the files are copied by the
+// client into a single initial commit; the template's history and other
+// branches are not carried over. The template is read before the repository
+// is created, and the new repository is deleted again if the initial commit
+// fails, so a failed call leaves no half-populated repository behind.
+func (s *RepositoriesService) CreateRepositoryFromTemplate(ctx context.Context, spaceRef string, opt *CreateRepositoryFromTemplateOptions) (*Repository, *Response, error) {
+	if opt == nil || opt.TemplateRef == nil || *opt.TemplateRef == "" {
+		return nil, nil, errors.New("template ref is required")
+	}
+	templateRef := *opt.TemplateRef
+
+	template, resp, err := s.GetRepository(ctx, templateRef)
+	if err != nil {
+		return nil, resp, err
+	}
+	paths, resp, err := s.ListPaths(ctx, templateRef, &ListPathsOptions{GitRef: template.DefaultBranch})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	actions := make([]*CommitFileAction, 0, len(paths.Files))
+	for _, file := range paths.Files {
+		content, resp, err := s.GetRawFile(ctx, templateRef, file, template.DefaultBranch)
+		if err != nil {
+			return nil, resp, fmt.Errorf("copy %q from template: %w", file, err)
+		}
+		actions = append(actions, CreateFile(file, string(content)))
+	}
+
+	branch := opt.DefaultBranch
+	if branch == nil {
+		branch = template.DefaultBranch
+	}
+	repository, resp, err := s.CreateRepository(ctx, spaceRef, &CreateRepositoryOptions{
+		Identifier:    opt.Identifier,
+		Description:   opt.Description,
+		IsPublic:      opt.IsPublic,
+		DefaultBranch: branch,
+	})
+	if err != nil || len(actions) == 0 {
+		return repository, resp, err
+	}
+	if repository.Path == nil {
+		return repository, resp, errors.New("created repository has no path")
+	}
+
+	message := "Create from template " + templateRef
+	if opt.Message != nil {
+		message = *opt.Message
+	}
+	_, resp, err = s.CommitFiles(ctx, *repository.Path, &CommitFilesOptions{
+		Actions: actions,
+		Branch:  repository.DefaultBranch,
+		Title:   &message,
+	})
+	if err != nil {
+		if _, delErr := s.DeleteRepository(ctx, *repository.Path, nil); delErr != nil {
+			return repository, resp, errors.Join(err, fmt.Errorf("delete %q: %w", *repository.Path, delErr))
+		}
+		return nil, resp, err
+	}
+	return repository, resp, nil
+}
+
+// UpdateRepository updates a repository. The update endpoint only accepts the
+// description, so DefaultBranch and IsPublic are applied through
+// UpdateDefaultBranch and UpdateRepositoryPublicAccess, and the description
+// is only sent when it is set or nothing else is. These are separate
+// requests: when one fails after another was applied, the error is a
+// *PartialUpdateError naming the applied fields.
 func (s *RepositoriesService) UpdateRepository(ctx context.Context, repoPath string, opt *UpdateRepositoryOptions) (*Repository, *Response, error) {
-	path := fmt.Sprintf("repos/%s", url.PathEscape(repoPath))
+	if opt == nil {
+		opt = &UpdateRepositoryOptions{}
+	}
+
+	if opt.DefaultBranch != nil && opt.ValidateDefaultBranch != nil && *opt.ValidateDefaultBranch {
+		if _, resp, err := s.GetBranch(ctx, repoPath, *opt.DefaultBranch); err != nil {
+			if isNotFound(err) {
+				return nil, resp, fmt.Errorf("%w: %q", ErrBranchNotFound, *opt.DefaultBranch)
+			}
+			return nil, resp, err
+		}
+	}
+
+	var (
+		repository *Repository
+		resp       *Response
+		err        error
+		applied    []string
+	)
+	if opt.Description != nil || (opt.DefaultBranch == nil && opt.IsPublic == nil) {
+		path := fmt.Sprintf("repos/%s", url.PathEscape(repoPath))
+		repository = &Repository{}
+		body := &struct {
+			Description *string `json:"description,omitempty"`
+		}{opt.Description}
+		resp, err = s.client.Patch(ctx, path, body, repository)
+		if err != nil {
+			return nil, resp, err
+		}
+		applied = append(applied, "description")
+	}
+	if opt.DefaultBranch != nil {
+		repository, resp, err = s.UpdateDefaultBranch(ctx, repoPath, *opt.DefaultBranch)
+		if err != nil {
+			return nil, resp, partialUpdate(applied, "default_branch", err)
+		}
+		applied = append(applied, "default_branch")
+	}
+	if opt.IsPublic != nil {
+		repository, resp, err = s.UpdateRepositoryPublicAccess(ctx, repoPath, *opt.IsPublic)
+		if err != nil {
+			return nil, resp, partialUpdate(applied, "is_public", err)
+		}
+	}
+	return repository, resp, nil
+}
+
+// UpdateDefaultBranchOptions specifies options for changing the default branch
+type UpdateDefaultBranchOptions struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// UpdateDefaultBranch changes the default branch of a repository. A 404 from
+// the server is reported as ErrBranchNotFound wrapping the API error.
+func (s *RepositoriesService) UpdateDefaultBranch(ctx context.Context, repoPath, branchName string) (*Repository, *Response, error) {
+	path := fmt.Sprintf("repos/%s/default-branch", url.PathEscape(repoPath))
+	var repository Repository
+	resp, err := s.client.Post(ctx, path, &UpdateDefaultBranchOptions{Name: Ptr(branchName)}, &repository)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, resp, fmt.Errorf("%w: %q: %w", ErrBranchNotFound, branchName, err)
+		}
+		return nil, resp, err
+	}
+	return &repository, resp, nil
+}
+
+// UpdatePublicAccessOptions specifies options for changing repository or space visibility
+type UpdatePublicAccessOptions struct {
+	IsPublic bool `json:"is_public"`
+}
+
+// UpdateRepositoryPublicAccess makes a repository public or private
+func (s *RepositoriesService) UpdateRepositoryPublicAccess(ctx context.Context, repoPath string, isPublic bool) (*Repository, *Response, error) {
+	path := fmt.Sprintf("repos/%s/public-access", url.PathEscape(repoPath))
 	var repository Repository
-	resp, err := s.client.Patch(ctx, path, opt, &repository)
+	resp, err := s.client.Post(ctx, path, &UpdatePublicAccessOptions{IsPublic: isPublic}, &repository)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -178,6 +425,155 @@ func (s *RepositoriesService) ListBranches(ctx context.Context, repoPath string,
 	return branches, resp, nil
 }
 
+// ListBranchesPaginated lists branches and returns a Page that can fetch the next page
+func (s *RepositoriesService) ListBranchesPaginated(ctx context.Context, repoPath string, opt *ListOptions) (*Page[Branch], error) {
+	var base ListOptions
+	if opt != nil {
+		base = *opt
+	}
+	return fetchPage(ctx, base.Page, func(ctx context.Context, page int) ([]*Branch, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListBranches(ctx, repoPath, &o)
+	})
+}
+
+// ListAllBranches lists branches across all pages, up to all.MaxResults
+func (s *RepositoriesService) ListAllBranches(ctx context.Context, repoPath string, opt *ListOptions, all *ListAllOptions) ([]*Branch, *Response, error) {
+	var base ListOptions
+	if opt != nil {
+		base = *opt
+	}
+	return listAllMax(ctx, all.maxResults(), func(ctx context.Context, page int) ([]*Branch, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListBranches(ctx, repoPath, &o)
+	})
+}
+
+// ListBranchesOptions specifies options for listing branches with details
+type ListBranchesOptions struct {
+	ListOptions
+	IncludeCommit *bool `url:"include_commit,omitempty"`
+	IncludeRules  *bool `url:"include_rules,omitempty"`
+
+	// ExcludeDefault skips the default branch in ListStaleBranches
+	ExcludeDefault *bool `url:"-"`
+	// ExcludeProtected skips branches with an active rule in ListStaleBranches
+	ExcludeProtected *bool `url:"-"`
+}
+
+// listBranchesExtended lists one page of branches with the optional details in opt
+func (s *RepositoriesService) listBranchesExtended(ctx context.Context, repoPath string, opt *ListBranchesOptions) ([]*Branch, *Response, error) {
+	path := fmt.Sprintf("repos/%s/branches", url.PathEscape(repoPath))
+	req := s.client.newRequest(ctx)
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.IncludeCommit != nil {
+		req.SetQueryParam("include_commit", strconv.FormatBool(*opt.IncludeCommit))
+	}
+	if opt.IncludeRules != nil {
+		req.SetQueryParam("include_rules", strconv.FormatBool(*opt.IncludeRules))
+	}
+
+	var branches []*Branch
+	req.SetSuccessResult(&branches)
+
+	resp, err := req.Get(s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if opt.IncludeRules != nil && *opt.IncludeRules {
+		for _, branch := range branches {
+			branch.IsProtected = Ptr(hasActiveRule(branch.Rules))
+		}
+	}
+
+	response := &Response{Response: resp}
+	s.client.parsePaginationHeaders(response)
+	return branches, response, nil
+}
+
+// ListBranchesWithDetails lists branches like ListBranches, optionally with
+// their latest commit and the rules protecting them. IsDefault is always
+// reported by the server; IsProtected is set when opt.IncludeRules is true.
+func (s *RepositoriesService) ListBranchesWithDetails(ctx context.Context, repoPath string, opt *ListBranchesOptions) ([]*Branch, *Response, error) {
+	if opt == nil {
+		opt = &ListBranchesOptions{}
+	}
+
+	return s.listBranchesExtended(ctx, repoPath, opt)
+}
+
+// ListStaleBranches lists branches whose latest commit is older than
+// olderThan, walking every page. Branches without a commit time are skipped.
+// The default branch and branches protected by an active rule are only
+// excluded when requested in opt.
+func (s *RepositoriesService) ListStaleBranches(ctx context.Context, repoPath string, olderThan time.Duration, opt *ListBranchesOptions) ([]*Branch, error) {
+	var base ListBranchesOptions
+	if opt != nil {
+		base = *opt
+	}
+	base.IncludeCommit = Ptr(true)
+	excludeDefault := base.ExcludeDefault != nil && *base.ExcludeDefault
+	excludeProtected := base.ExcludeProtected != nil && *base.ExcludeProtected
+	if excludeProtected {
+		base.IncludeRules = Ptr(true)
+	}
+
+	branches, _, err := listAll(ctx, func(ctx context.Context, page int) ([]*Branch, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.listBranchesExtended(ctx, repoPath, &o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := s.client.clock.Now().Add(-olderThan)
+	var stale []*Branch
+	for _, branch := range branches {
+		if excludeDefault && branch.IsDefault != nil && *branch.IsDefault {
+			continue
+		}
+		if excludeProtected && hasActiveRule(branch.Rules) {
+			continue
+		}
+		if when := branch.Commit.lastChanged(); !when.IsZero() && when.Before(cutoff) {
+			stale = append(stale, branch)
+		}
+	}
+	return stale, nil
+}
+
+// hasActiveRule reports whether any of rules is enforced
+func hasActiveRule(rules []*RuleInfo) bool {
+	for _, rule := range rules {
+		if rule != nil && rule.State != nil && *rule.State == "active" {
+			return true
+		}
+	}
+	return false
+}
+
+// lastChanged returns the committer time of the commit, falling back to the
+// author time, or the zero time when neither is known
+func (c *CommitSHA) lastChanged() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	for _, who := range []*Committer{c.Committer, c.Author} {
+		if who != nil && who.When != nil && !time.Time(*who.When).IsZero() {
+			return time.Time(*who.When)
+		}
+	}
+	return time.Time{}
+}
+
 // GetBranch retrieves a specific branch
 func (s *RepositoriesService) GetBranch(ctx context.Context, repoPath, branchName string) (*Branch, *Response, error) {
 	path := fmt.Sprintf("repos/%s/branches/%s", url.PathEscape(repoPath), url.PathEscape(branchName))
@@ -189,6 +585,11 @@ func (s *RepositoriesService) GetBranch(ctx context.Context, repoPath, branchNam
 	return &branch, resp, nil
 }
 
+// GetBranchOrNil is like GetBranch but returns a nil Branch without an error when the branch does not exist
+func (s *RepositoriesService) GetBranchOrNil(ctx context.Context, repoPath, branchName string) (*Branch, *Response, error) {
+	return notFoundAsNil(s.GetBranch(ctx, repoPath, branchName))
+}
+
 // CreateBranch creates a new branch
 func (s *RepositoriesService) CreateBranch(ctx context.Context, repoPath string, opt *CreateBranchOptions) (*Branch, *Response, error) {
 	path := fmt.Sprintf("repos/%s/branches", url.PathEscape(repoPath))
@@ -215,13 +616,123 @@ func (s *RepositoriesService) DeleteBranch(ctx context.Context, repoPath, branch
 
 // Commit represents a git commit
 type Commit struct {
-	SHA       *string    `json:"sha,omitempty"`
-	Message   *string    `json:"message,omitempty"`
-	Author    *Signature `json:"author,omitempty"`
-	Committer *Signature `json:"committer,omitempty"`
-	Added     []string   `json:"added,omitempty"`
-	Removed   []string   `json:"removed,omitempty"`
-	Modified  []string   `json:"modified,omitempty"`
+	SHA       *string      `json:"sha,omitempty"`
+	Message   *string      `json:"message,omitempty"`
+	Author    *Signature   `json:"author,omitempty"`
+	Committer *Signature   `json:"committer,omitempty"`
+	Added     []string     `json:"added,omitempty"`
+	Removed   []string     `json:"removed,omitempty"`
+	Modified  []string     `json:"modified,omitempty"`
+	Stats     *CommitStats `json:"stats,omitempty"`
+
+	// Signature is the raw signature verification result, nil for unsigned commits
+	Signature *GitSignature `json:"signature,omitempty"`
+	// Verification summarizes Signature; it is nil for unsigned commits
+	Verification *CommitVerification `json:"-"`
+}
+
+// GitSignatureResult is the outcome of verifying a commit signature
+type GitSignatureResult string
+
+// GitSignatureResult constants
+const (
+	GitSignatureResultGood        GitSignatureResult = "good"
+	GitSignatureResultBad         GitSignatureResult = "bad"
+	GitSignatureResultInvalid     GitSignatureResult = "invalid"
+	GitSignatureResultKeyExpired  GitSignatureResult = "key_expired"
+	GitSignatureResultRevoked     GitSignatureResult = "revoked"
+	GitSignatureResultUnsupported GitSignatureResult = "unsupported"
+	GitSignatureResultUnverified  GitSignatureResult = "unverified"
+)
+
+// GitSignature represents the verification result of a commit signature
+type GitSignature struct {
+	Result         *GitSignatureResult `json:"result,omitempty"`
+	KeyID          *string             `json:"key_id,omitempty"`
+	KeyFingerprint *string             `json:"key_fingerprint,omitempty"`
+	KeyScheme      *string             `json:"key_scheme,omitempty"`
+	Created        *int64              `json:"created,omitempty"`
+	Updated        *int64              `json:"updated,omitempty"`
+}
+
+// CommitVerification summarizes whether a commit signature was verified.
+// Gitness identifies the signer by key rather than by principal.
+type CommitVerification struct {
+	Verified       bool
+	Reason         GitSignatureResult
+	KeyID          string
+	KeyFingerprint string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface and fills
+// Verification from the signature result
+func (c *Commit) UnmarshalJSON(data []byte) error {
+	type commit Commit
+	if err := json.Unmarshal(data, (*commit)(c)); err != nil {
+		return err
+	}
+
+	c.Verification = nil
+	if sig := c.Signature; sig != nil && sig.Result != nil {
+		c.Verification = &CommitVerification{
+			Verified: *sig.Result == GitSignatureResultGood,
+			Reason:   *sig.Result,
+		}
+		if sig.KeyID != nil {
+			c.Verification.KeyID = *sig.KeyID
+		}
+		if sig.KeyFingerprint != nil {
+			c.Verification.KeyFingerprint = *sig.KeyFingerprint
+		}
+	}
+	return nil
+}
+
+// ChangeStats represents line change counts
+type ChangeStats struct {
+	Changes    *int `json:"changes,omitempty"`
+	Insertions *int `json:"insertions,omitempty"`
+	Deletions  *int `json:"deletions,omitempty"`
+}
+
+// CommitFileStats represents line change counts of a single file in a commit
+type CommitFileStats struct {
+	Path       *string `json:"path,omitempty"`
+	OldPath    *string `json:"old_path,omitempty"`
+	Status     *string `json:"status,omitempty"`
+	Changes    *int    `json:"changes,omitempty"`
+	Insertions *int    `json:"insertions,omitempty"`
+	Deletions  *int    `json:"deletions,omitempty"`
+}
+
+// CommitStats represents the change statistics of a commit
+type CommitStats struct {
+	Total *ChangeStats       `json:"total,omitempty"`
+	Files []*CommitFileStats `json:"files,omitempty"`
+}
+
+// Additions returns the number of added lines, or 0 if stats were not requested
+func (c *Commit) Additions() int {
+	if c.Stats == nil || c.Stats.Total == nil || c.Stats.Total.Insertions == nil {
+		return 0
+	}
+	return *c.Stats.Total.Insertions
+}
+
+// Deletions returns the number of deleted lines, or 0 if stats were not requested
+func (c *Commit) Deletions() int {
+	if c.Stats == nil || c.Stats.Total == nil || c.Stats.Total.Deletions == nil {
+		return 0
+	}
+	return *c.Stats.Total.Deletions
+}
+
+// FilesChanged returns the number of changed files, or 0 if stats were not requested
+func (c *Commit) FilesChanged() int {
+	if c.Stats == nil {
+		return 0
+	}
+	return len(c.Stats.Files)
 }
 
 // Signature represents a git signature
@@ -233,33 +744,154 @@ type Signature struct {
 // ListCommits lists commits in a repository
 func (s *RepositoriesService) ListCommits(ctx context.Context, repoPath string, opt *ListCommitsOptions) ([]*Commit, *Response, error) {
 	path := fmt.Sprintf("repos/%s/commits", url.PathEscape(repoPath))
-	req := s.client.client.R().SetContext(ctx)
 
-	// Add query parameters if options provided
+	var list commitList
+	resp, err := s.client.performConditionalListRequest(ctx, path, func(req httpRequest) {
+		s.setListCommitsParams(req, opt)
+		if opt != nil && opt.IfModifiedSince != nil {
+			req.SetHeader("If-Modified-Since", opt.IfModifiedSince.UTC().Format(http.TimeFormat))
+		}
+	}, &list)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp.Total == nil && list.TotalCommits != nil {
+		resp.Total = Ptr(*list.TotalCommits)
+	}
+	return list.Commits, resp, nil
+}
+
+// ListAllCommits lists commits across all pages, newest first, up to
+// all.MaxResults. Set a cap on large repositories, where walking the whole
+// history can mean millions of commits.
+func (s *RepositoriesService) ListAllCommits(ctx context.Context, repoPath string, opt *ListCommitsOptions, all *ListAllOptions) ([]*Commit, *Response, error) {
+	var base ListCommitsOptions
 	if opt != nil {
-		// Add common query parameters
-		buildQueryParams(req, &opt.ListOptions)
+		base = *opt
+	}
+	return listAllMax(ctx, all.maxResults(), func(ctx context.Context, page int) ([]*Commit, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListCommits(ctx, repoPath, &o)
+	})
+}
 
-		// Add specific query parameters
-		if opt.GitRef != nil {
-			req.SetQueryParam("git_ref", *opt.GitRef)
-		}
-		if opt.After != nil {
-			req.SetQueryParam("after", *opt.After)
-		}
-		if opt.Since != nil {
-			req.SetQueryParam("since", opt.Since.String())
-		}
-		if opt.Until != nil {
-			req.SetQueryParam("until", opt.Until.String())
-		}
-		if opt.Path != nil {
-			req.SetQueryParam("path", *opt.Path)
+// StreamCommits lists commits like ListCommits but decodes the response
+// incrementally, calling fn for each commit instead of buffering the whole
+// page. It stops and returns fn's error as soon as fn fails.
+func (s *RepositoriesService) StreamCommits(ctx context.Context, repoPath string, opt *ListCommitsOptions, fn func(*Commit) error) (*Response, error) {
+	path := fmt.Sprintf("repos/%s/commits", url.PathEscape(repoPath))
+	req := s.client.newRequest(ctx).DisableAutoReadResponse()
+	s.setListCommitsParams(req, opt)
+
+	resp, err := req.Get(s.client.buildFullURL(path))
+	if err != nil {
+		return &Response{Response: resp}, err
+	}
+	defer resp.Body.Close()
+
+	response := &Response{Response: resp}
+	if !resp.IsSuccessState() {
+		if _, err := resp.ToBytes(); err != nil {
+			return response, err
 		}
+		return response, s.client.checkResponse(resp)
+	}
+	s.client.parsePaginationHeaders(response)
+
+	body := newContextBody(ctx, resp.Body)
+	defer body.Close()
+	return response, streamJSONArray(body, "commits", fn)
+}
+
+// ListCommitsOptions specifies options for listing commits
+type ListCommitsOptions struct {
+	ListOptions
+	GitRef       *string `url:"git_ref,omitempty"`
+	After        *string `url:"after,omitempty"`
+	Since        *Time   `url:"since,omitempty"`
+	Until        *Time   `url:"until,omitempty"`
+	Path         *string `url:"path,omitempty"`
+	IncludeStats *bool   `url:"include_stats,omitempty"`
+
+	// IfModifiedSince makes ListCommits a conditional request. When nothing
+	// changed since the given time (for example a repository's previous
+	// Updated value) the server answers 304 and ListCommits returns no commits
+	// with Response.NotModified reporting true.
+	IfModifiedSince *time.Time `url:"-"`
+}
+
+// setListCommitsParams adds the commit listing query parameters to a request
+func (s *RepositoriesService) setListCommitsParams(req httpRequest, opt *ListCommitsOptions) {
+	if opt == nil {
+		opt = &ListCommitsOptions{}
 	}
 
-	var commits []*Commit
-	req.SetSuccessResult(&commits)
+	// Add common query parameters
+	s.client.buildQueryParams(req, &opt.ListOptions)
+
+	// Add specific query parameters
+	if opt.GitRef != nil {
+		req.SetQueryParam("git_ref", *opt.GitRef)
+	}
+	if opt.After != nil {
+		req.SetQueryParam("after", *opt.After)
+	}
+	if opt.Since != nil {
+		req.SetQueryParam("since", opt.Since.String())
+	}
+	if opt.Until != nil {
+		req.SetQueryParam("until", opt.Until.String())
+	}
+	if opt.Path != nil {
+		req.SetQueryParam("path", *opt.Path)
+	}
+	if opt.IncludeStats != nil {
+		req.SetQueryParam("include_stats", fmt.Sprintf("%t", *opt.IncludeStats))
+	}
+}
+
+// RenameDetails describes a rename of a file between two commits
+type RenameDetails struct {
+	OldPath         *string `json:"old_path,omitempty"`
+	NewPath         *string `json:"new_path,omitempty"`
+	CommitSHABefore *string `json:"commit_sha_before,omitempty"`
+	CommitSHAAfter  *string `json:"commit_sha_after,omitempty"`
+}
+
+// FileHistory represents the commits touching a file and the renames it went through
+type FileHistory struct {
+	Commits       []*Commit        `json:"commits,omitempty"`
+	RenameDetails []*RenameDetails `json:"rename_details,omitempty"`
+	TotalCommits  *int             `json:"total_commits,omitempty"`
+}
+
+// commitList decodes the commit list endpoint. The API returns a
+// TypesListCommitResponse object; a bare array, as sent by older servers, is
+// accepted too.
+type commitList FileHistory
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (l *commitList) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		*l = commitList{}
+		return json.Unmarshal(data, &l.Commits)
+	}
+	return json.Unmarshal(data, (*FileHistory)(l))
+}
+
+// GetFileHistory lists the commits touching a file, following it across renames.
+// The rename chain is reported in FileHistory.RenameDetails.
+func (s *RepositoriesService) GetFileHistory(ctx context.Context, repoPath, filePath string, opt *ListCommitsOptions) (*FileHistory, *Response, error) {
+	path := fmt.Sprintf("repos/%s/commits", url.PathEscape(repoPath))
+	req := s.client.newRequest(ctx)
+
+	s.setListCommitsParams(req, opt)
+	req.SetQueryParam("path", filePath)
+
+	var history commitList
+	req.SetSuccessResult(&history)
 
 	fullURL := s.client.buildFullURL(path)
 	resp, err := req.Get(fullURL)
@@ -271,25 +903,42 @@ func (s *RepositoriesService) ListCommits(ctx context.Context, repoPath string,
 		return nil, &Response{Response: resp}, err
 	}
 
-	// Parse pagination headers
 	response := &Response{Response: resp}
 	s.client.parsePaginationHeaders(response)
 
-	return commits, response, nil
+	return (*FileHistory)(&history), response, nil
 }
 
-// ListCommitsOptions specifies options for listing commits
-type ListCommitsOptions struct {
-	ListOptions
-	GitRef *string `url:"git_ref,omitempty"`
-	After  *string `url:"after,omitempty"`
-	Since  *Time   `url:"since,omitempty"`
-	Until  *Time   `url:"until,omitempty"`
-	Path   *string `url:"path,omitempty"`
+// GetCommitOptions specifies options for retrieving a single commit
+type GetCommitOptions struct {
+	// IncludeStats adds line and file change statistics to the commit
+	IncludeStats *bool
 }
 
 // GetCommit retrieves a specific commit
 func (s *RepositoriesService) GetCommit(ctx context.Context, repoPath, commitSHA string) (*Commit, *Response, error) {
+	return s.GetCommitWithOptions(ctx, repoPath, commitSHA, nil)
+}
+
+// GetCommitWithOptions is like GetCommit but accepts options. The
+// single-commit endpoint does not report statistics, so with IncludeStats the
+// commit is read from the commit list endpoint, starting at commitSHA, instead.
+func (s *RepositoriesService) GetCommitWithOptions(ctx context.Context, repoPath, commitSHA string, opt *GetCommitOptions) (*Commit, *Response, error) {
+	if opt != nil && opt.IncludeStats != nil && *opt.IncludeStats {
+		commits, resp, err := s.ListCommits(ctx, repoPath, &ListCommitsOptions{
+			ListOptions:  ListOptions{Page: Ptr(1), Limit: Ptr(1)},
+			GitRef:       Ptr(commitSHA),
+			IncludeStats: Ptr(true),
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+		if len(commits) == 0 {
+			return nil, resp, fmt.Errorf("%w: commit %q", ErrNotFound, commitSHA)
+		}
+		return commits[0], resp, nil
+	}
+
 	path := fmt.Sprintf("repos/%s/commits/%s", url.PathEscape(repoPath), url.PathEscape(commitSHA))
 	var commit Commit
 	resp, err := s.client.Get(ctx, path, &commit)
@@ -320,6 +969,36 @@ func (s *RepositoriesService) GetFileContent(ctx context.Context, repoPath, file
 	return &fileContent, resp, nil
 }
 
+// GetRawFile retrieves the raw content of a file. gitRef selects the branch,
+// tag or commit; nil uses the default branch.
+func (s *RepositoriesService) GetRawFile(ctx context.Context, repoPath, filePath string, gitRef *string) ([]byte, *Response, error) {
+	path := fmt.Sprintf("repos/%s/raw/%s", url.PathEscape(repoPath), escapeFilePath(filePath))
+	req := s.client.newRequest(ctx)
+	if gitRef != nil {
+		req.SetQueryParam("git_ref", *gitRef)
+	}
+
+	resp, err := req.Get(s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+	return resp.Bytes(), &Response{Response: resp}, nil
+}
+
+// escapeFilePath escapes each segment of a repository file path, keeping the
+// separators, for endpoints that take the path as trailing URL segments
+func escapeFilePath(filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
 // GetFileOptions specifies options for getting file content
 type GetFileOptions struct {
 	Ref           *string `url:"git_ref,omitempty"`
@@ -336,24 +1015,74 @@ type TreeNode struct {
 	Size *int64  `json:"size,omitempty"`
 }
 
-// ListPaths lists paths in a repository tree
-func (s *RepositoriesService) ListPaths(ctx context.Context, repoPath string, opt *ListPathsOptions) ([]*TreeNode, *Response, error) {
+// RepoPaths represents the files and, when requested, directories of a repository tree
+type RepoPaths struct {
+	Files       []string `json:"files,omitempty"`
+	Directories []string `json:"directories,omitempty"`
+}
+
+// ListPaths lists the paths of all files in a repository tree, and of all
+// directories when IncludeDirectories is set
+func (s *RepositoriesService) ListPaths(ctx context.Context, repoPath string, opt *ListPathsOptions) (*RepoPaths, *Response, error) {
 	path := fmt.Sprintf("repos/%s/paths", url.PathEscape(repoPath))
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx)
 
 	// Add specific query parameters
-	if opt.GitRef != nil {
-		req.SetQueryParam("git_ref", *opt.GitRef)
+	if opt != nil {
+		if opt.GitRef != nil {
+			req.SetQueryParam("git_ref", *opt.GitRef)
+		}
+		if opt.Path != nil {
+			req.SetQueryParam("path", *opt.Path)
+		}
+		if opt.IncludeCommit != nil {
+			req.SetQueryParam("include_commit", fmt.Sprintf("%t", *opt.IncludeCommit))
+		}
+		if opt.IncludeDirectories != nil {
+			req.SetQueryParam("include_directories", fmt.Sprintf("%t", *opt.IncludeDirectories))
+		}
 	}
-	if opt.Path != nil {
-		req.SetQueryParam("path", *opt.Path)
+
+	var paths RepoPaths
+	req.SetSuccessResult(&paths)
+
+	fullURL := s.client.buildFullURL(path)
+	resp, err := req.Get(fullURL)
+	if err != nil {
+		return nil, &Response{Response: resp}, err
 	}
-	if opt.IncludeCommit != nil {
-		req.SetQueryParam("include_commit", fmt.Sprintf("%t", *opt.IncludeCommit))
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
 	}
 
-	var nodes []*TreeNode
-	req.SetSuccessResult(&nodes)
+	return &paths, &Response{Response: resp}, nil
+}
+
+// directoryContent represents the content endpoint output for a directory
+type directoryContent struct {
+	Type    *string `json:"type,omitempty"`
+	Content *struct {
+		Entries []*TreeNode `json:"entries,omitempty"`
+	} `json:"content,omitempty"`
+}
+
+// ListDirectory lists the immediate children of a directory at a git ref
+func (s *RepositoriesService) ListDirectory(ctx context.Context, repoPath, dirPath string, opt *GetFileOptions) ([]*TreeNode, *Response, error) {
+	path := fmt.Sprintf("repos/%s/content/%s", url.PathEscape(repoPath), escapeFilePath(dirPath))
+	req := s.client.newRequest(ctx)
+
+	if opt != nil {
+		if opt.Ref != nil {
+			req.SetQueryParam("git_ref", *opt.Ref)
+		}
+		if opt.IncludeCommit != nil {
+			req.SetQueryParam("include_commit", fmt.Sprintf("%t", *opt.IncludeCommit))
+		}
+	}
+
+	var content directoryContent
+	req.SetSuccessResult(&content)
 
 	fullURL := s.client.buildFullURL(path)
 	resp, err := req.Get(fullURL)
@@ -365,18 +1094,24 @@ func (s *RepositoriesService) ListPaths(ctx context.Context, repoPath string, op
 		return nil, &Response{Response: resp}, err
 	}
 
-	// Parse pagination headers
-	response := &Response{Response: resp}
-	s.client.parsePaginationHeaders(response)
+	if content.Type == nil || *content.Type != "dir" {
+		return nil, &Response{Response: resp}, fmt.Errorf("path %q is not a directory", dirPath)
+	}
 
-	return nodes, response, nil
+	var nodes []*TreeNode
+	if content.Content != nil {
+		nodes = content.Content.Entries
+	}
+
+	return nodes, &Response{Response: resp}, nil
 }
 
 // ListPathsOptions specifies options for listing paths
 type ListPathsOptions struct {
-	GitRef        *string `url:"git_ref,omitempty"`
-	Path          *string `url:"path,omitempty"`
-	IncludeCommit *bool   `url:"include_commit,omitempty"`
+	GitRef             *string `url:"git_ref,omitempty"`
+	Path               *string `url:"path,omitempty"`
+	IncludeCommit      *bool   `url:"include_commit,omitempty"`
+	IncludeDirectories *bool   `url:"include_directories,omitempty"`
 }
 
 // Tag represents a git tag
@@ -401,11 +1136,15 @@ type ListTagsOptions struct {
 
 // ListTags lists repository tags
 func (s *RepositoriesService) ListTags(ctx context.Context, repoPath string, opt *ListTagsOptions) ([]*Tag, *Response, error) {
+	if opt == nil {
+		opt = &ListTagsOptions{}
+	}
+
 	path := fmt.Sprintf("repos/%s/tags", url.PathEscape(repoPath))
-	req := s.client.client.R().SetContext(ctx)
 
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	var tags []*Tag
+	resp, err := s.client.performListRequestWithParams(ctx, path, func(req httpRequest) {
+		s.client.buildQueryParams(req, &opt.ListOptions)
 		if opt.Query != nil {
 			req.SetQueryParam("query", *opt.Query)
 		}
@@ -418,25 +1157,37 @@ func (s *RepositoriesService) ListTags(ctx context.Context, repoPath string, opt
 		if opt.IncludeCommit != nil {
 			req.SetQueryParam("include_commit", fmt.Sprintf("%t", *opt.IncludeCommit))
 		}
+	}, &tags)
+	if err != nil {
+		return nil, resp, err
 	}
 
-	var tags []*Tag
-	req.SetSuccessResult(&tags)
+	return tags, resp, nil
+}
 
-	fullURL := s.client.buildFullURL(path)
-	resp, err := req.Get(fullURL)
+// ErrTagNotFound is returned when a tag does not exist
+var ErrTagNotFound = errors.New("tag not found")
+
+// GetTag retrieves a single tag, including the tagger and message of
+// annotated tags and the commit it points to. The API has no endpoint for a
+// single tag, so the tag list is searched for tagName.
+func (s *RepositoriesService) GetTag(ctx context.Context, repoPath, tagName string) (*Tag, *Response, error) {
+	tags, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*Tag, *Response, error) {
+		return s.ListTags(ctx, repoPath, &ListTagsOptions{
+			ListOptions:   ListOptions{Page: Ptr(page), Limit: Ptr(100)},
+			Query:         Ptr(tagName),
+			IncludeCommit: Ptr(true),
+		})
+	})
 	if err != nil {
-		return nil, &Response{Response: resp}, err
+		return nil, resp, err
 	}
-
-	if err := s.client.checkResponse(resp); err != nil {
-		return nil, &Response{Response: resp}, err
+	for _, tag := range tags {
+		if tag.Name != nil && *tag.Name == tagName {
+			return tag, resp, nil
+		}
 	}
-
-	response := &Response{Response: resp}
-	s.client.parsePaginationHeaders(response)
-
-	return tags, response, nil
+	return nil, resp, fmt.Errorf("%w: %q", ErrTagNotFound, tagName)
 }
 
 // CreateTagOptions specifies options for creating a tag
@@ -505,13 +1256,93 @@ func (s *RepositoriesService) DeleteTag(ctx context.Context, repoPath, tagName s
 	return &output, resp, nil
 }
 
+// CommitAction represents the type of a file action in a commit
+type CommitAction string
+
+// Commit action constants
+const (
+	CommitActionCreate    CommitAction = "CREATE"
+	CommitActionUpdate    CommitAction = "UPDATE"
+	CommitActionDelete    CommitAction = "DELETE"
+	CommitActionMove      CommitAction = "MOVE"
+	CommitActionPatchText CommitAction = "PATCH_TEXT"
+)
+
 // CommitFileAction represents a file action in a commit
 type CommitFileAction struct {
-	Action   *string `json:"action,omitempty"`
-	Path     *string `json:"path,omitempty"`
-	Payload  *string `json:"payload,omitempty"`
-	SHA      *string `json:"sha,omitempty"`
-	Encoding *string `json:"encoding,omitempty"`
+	Action   *CommitAction `json:"action,omitempty"`
+	Path     *string       `json:"path,omitempty"`
+	Payload  *string       `json:"payload,omitempty"`
+	SHA      *string       `json:"sha,omitempty"`
+	Encoding *string       `json:"encoding,omitempty"`
+}
+
+// Content encodings of a commit file action payload
+const (
+	ContentEncodingUTF8   = "utf8"
+	ContentEncodingBase64 = "base64"
+)
+
+// setPayload stores content as the action payload. Text is sent as is while
+// binary content is base64-encoded so it survives the JSON request body.
+func (a *CommitFileAction) setPayload(content string) {
+	if utf8.ValidString(content) && !strings.ContainsRune(content, 0) {
+		a.Payload = &content
+		return
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	a.Payload = &encoded
+	a.Encoding = Ptr(ContentEncodingBase64)
+}
+
+// CreateFile builds an action that creates a file with the given content.
+// Binary content is base64-encoded automatically.
+func CreateFile(path, content string) *CommitFileAction {
+	action := &CommitFileAction{
+		Action: Ptr(CommitActionCreate),
+		Path:   &path,
+	}
+	action.setPayload(content)
+	return action
+}
+
+// UpdateFile builds an action that replaces the content of a file.
+// The sha is the blob SHA of the current file and is optional.
+// Binary content is base64-encoded automatically.
+func UpdateFile(path, content, sha string) *CommitFileAction {
+	action := &CommitFileAction{
+		Action: Ptr(CommitActionUpdate),
+		Path:   &path,
+	}
+	action.setPayload(content)
+	if sha != "" {
+		action.SHA = &sha
+	}
+	return action
+}
+
+// DeleteFile builds an action that deletes a file.
+// The sha is the blob SHA of the current file and is optional.
+func DeleteFile(path, sha string) *CommitFileAction {
+	action := &CommitFileAction{
+		Action: Ptr(CommitActionDelete),
+		Path:   &path,
+	}
+	if sha != "" {
+		action.SHA = &sha
+	}
+	return action
+}
+
+// MoveFile builds an action that renames a file. Gitness expects the source
+// in Path and the destination path as the payload.
+func MoveFile(from, to string) *CommitFileAction {
+	return &CommitFileAction{
+		Action:  Ptr(CommitActionMove),
+		Path:    &from,
+		Payload: &to,
+	}
 }
 
 // CommitFilesOptions specifies options for committing files
@@ -524,6 +1355,9 @@ type CommitFilesOptions struct {
 	Author      *Identity           `json:"author,omitempty"`
 	BypassRules *bool               `json:"bypass_rules,omitempty"`
 	DryRunRules *bool               `json:"dry_run_rules,omitempty"`
+
+	// IncludeCommit fetches the created commit and embeds it in the response
+	IncludeCommit *bool `json:"-"`
 }
 
 // FileReference represents a file reference
@@ -538,6 +1372,9 @@ type CommitFilesResponse struct {
 	ChangedFiles   []*FileReference `json:"changed_files,omitempty"`
 	DryRunRules    *bool            `json:"dry_run_rules,omitempty"`
 	RuleViolations []*RuleViolation `json:"rule_violations,omitempty"`
+
+	// Commit is populated when CommitFilesOptions.IncludeCommit is set
+	Commit *Commit `json:"commit,omitempty"`
 }
 
 // CommitFiles commits files to a repository
@@ -548,6 +1385,48 @@ func (s *RepositoriesService) CommitFiles(ctx context.Context, repoPath string,
 	if err != nil {
 		return nil, resp, err
 	}
+
+	// The API only returns the commit SHA, so fetch the details on request
+	if opt != nil && opt.IncludeCommit != nil && *opt.IncludeCommit && output.CommitID != nil && *output.CommitID != "" {
+		commit, commitResp, err := s.GetCommit(ctx, repoPath, *output.CommitID)
+		if err != nil {
+			return &output, commitResp, err
+		}
+		output.Commit = commit
+	}
+
+	return &output, resp, nil
+}
+
+// RebaseBranchOptions specifies options for rebasing a branch
+type RebaseBranchOptions struct {
+	BaseBranch    *string `json:"base_branch,omitempty"`
+	BaseCommitSHA *string `json:"base_commit_sha,omitempty"`
+	HeadBranch    *string `json:"head_branch,omitempty"`
+	HeadCommitSHA *string `json:"head_commit_sha,omitempty"`
+	BypassRules   *bool   `json:"bypass_rules,omitempty"`
+	DryRun        *bool   `json:"dry_run,omitempty"`
+	DryRunRules   *bool   `json:"dry_run_rules,omitempty"`
+}
+
+// RebaseBranchOutput represents the response from rebasing a branch
+type RebaseBranchOutput struct {
+	AlreadyAncestor  *bool            `json:"already_ancestor,omitempty"`
+	ConflictFiles    []string         `json:"conflict_files,omitempty"`
+	DryRun           *bool            `json:"dry_run,omitempty"`
+	DryRunRules      *bool            `json:"dry_run_rules,omitempty"`
+	NewHeadBranchSHA *string          `json:"new_head_branch_sha,omitempty"`
+	RuleViolations   []*RuleViolation `json:"rule_violations,omitempty"`
+}
+
+// RebaseBranch rebases the head branch onto the base branch
+func (s *RepositoriesService) RebaseBranch(ctx context.Context, repoPath string, opt *RebaseBranchOptions) (*RebaseBranchOutput, *Response, error) {
+	path := fmt.Sprintf("repos/%s/rebase", url.PathEscape(repoPath))
+	var output RebaseBranchOutput
+	resp, err := s.client.Post(ctx, path, opt, &output)
+	if err != nil {
+		return nil, resp, err
+	}
 	return &output, resp, nil
 }
 
@@ -556,10 +1435,12 @@ type GetCommitDiffOptions struct {
 	IgnoreWhitespace *bool `url:"ignore_whitespace,omitempty"`
 }
 
-// GetCommitDiff retrieves the diff for a specific commit
+// GetCommitDiff retrieves the diff for a specific commit as raw unified diff
+// text. Use GetDiff with DiffFormatJSON for per-file changes.
 func (s *RepositoriesService) GetCommitDiff(ctx context.Context, repoPath, commitSHA string, opt *GetCommitDiffOptions) (string, *Response, error) {
 	path := fmt.Sprintf("repos/%s/commits/%s/diff", url.PathEscape(repoPath), url.PathEscape(commitSHA))
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx).
+		SetHeader("Accept", diffAcceptRaw)
 
 	if opt != nil && opt.IgnoreWhitespace != nil {
 		req.SetQueryParam("ignore_whitespace", fmt.Sprintf("%t", *opt.IgnoreWhitespace))
@@ -578,6 +1459,121 @@ func (s *RepositoriesService) GetCommitDiff(ctx context.Context, repoPath, commi
 	return resp.String(), &Response{Response: resp}, nil
 }
 
+// DiffFormat selects the representation returned by GetDiff
+type DiffFormat string
+
+// DiffFormat constants
+const (
+	DiffFormatRaw  DiffFormat = "raw"
+	DiffFormatJSON DiffFormat = "json"
+)
+
+// Accept headers the diff endpoints use to pick a representation
+const (
+	diffAcceptRaw  = "text/plain"
+	diffAcceptJSON = "application/json"
+)
+
+// FileDiff represents the changes to a single file in a diff
+type FileDiff struct {
+	SHA         *string         `json:"sha,omitempty"`
+	OldSHA      *string         `json:"old_sha,omitempty"`
+	Path        *string         `json:"path,omitempty"`
+	OldPath     *string         `json:"old_path,omitempty"`
+	Status      *FileDiffStatus `json:"status,omitempty"`
+	Additions   *int            `json:"additions,omitempty"`
+	Deletions   *int            `json:"deletions,omitempty"`
+	Changes     *int            `json:"changes,omitempty"`
+	IsBinary    *bool           `json:"is_binary,omitempty"`
+	IsSubmodule *bool           `json:"is_submodule,omitempty"`
+	Patch       []byte          `json:"patch,omitempty"`
+
+	// Hunks is filled by ParseUnifiedDiff
+	Hunks []*DiffHunk `json:"hunks,omitempty"`
+}
+
+// GetDiffOptions specifies options for getting a diff between two refs
+type GetDiffOptions struct {
+	IgnoreWhitespace *bool       `url:"ignore_whitespace,omitempty"`
+	Format           *DiffFormat `url:"-"`
+}
+
+// Diff holds a diff in the requested format: Raw for DiffFormatRaw, Files for DiffFormatJSON
+type Diff struct {
+	Raw   string
+	Files []*FileDiff
+}
+
+// GetDiff retrieves the diff between two refs. The format defaults to
+// DiffFormatRaw; DiffFormatJSON returns per-file changes. For a single
+// commit use base "<sha>^" and head "<sha>".
+func (s *RepositoriesService) GetDiff(ctx context.Context, repoPath, base, head string, opt *GetDiffOptions) (*Diff, *Response, error) {
+	path := fmt.Sprintf("repos/%s/diff/%s", url.PathEscape(repoPath), refRange(base, head))
+	req := s.client.newRequest(ctx)
+
+	format := DiffFormatRaw
+	if opt != nil {
+		if opt.Format != nil {
+			format = *opt.Format
+		}
+		if opt.IgnoreWhitespace != nil {
+			req.SetQueryParam("ignore_whitespace", fmt.Sprintf("%t", *opt.IgnoreWhitespace))
+		}
+	}
+
+	var diff Diff
+	switch format {
+	case DiffFormatRaw:
+		req.SetHeader("Accept", diffAcceptRaw)
+	case DiffFormatJSON:
+		req.SetHeader("Accept", diffAcceptJSON)
+	default:
+		return nil, nil, fmt.Errorf("unknown diff format %q", format)
+	}
+
+	resp, err := req.Get(s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if format == DiffFormatRaw {
+		diff.Raw = resp.String()
+	} else if err := s.client.decodeResponse(resp, &diff.Files); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	return &diff, &Response{Response: resp}, nil
+}
+
+// refRange builds a "base...head" range path segment, escaping each ref so
+// branch names such as feature/x stay within a single segment
+func refRange(base, head string) string {
+	return url.PathEscape(base) + "..." + url.PathEscape(head)
+}
+
+// DiffStats represents aggregate statistics for a diff between two refs
+type DiffStats struct {
+	Commits      *int `json:"commits,omitempty"`
+	FilesChanged *int `json:"files_changed,omitempty"`
+	Additions    *int `json:"additions,omitempty"`
+	Deletions    *int `json:"deletions,omitempty"`
+}
+
+// CompareRefs returns diff statistics between two refs (branches, tags or commit SHAs)
+func (s *RepositoriesService) CompareRefs(ctx context.Context, repoPath, base, head string) (*DiffStats, *Response, error) {
+	path := fmt.Sprintf("repos/%s/diff-stats/%s", url.PathEscape(repoPath), refRange(base, head))
+	var stats DiffStats
+	resp, err := s.client.Get(ctx, path, &stats)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &stats, resp, nil
+}
+
 // CommitDivergenceRequest represents a divergence calculation request
 type CommitDivergenceRequest struct {
 	From *string `json:"from,omitempty"`
@@ -606,3 +1602,217 @@ func (s *RepositoriesService) CalculateCommitDivergence(ctx context.Context, rep
 	}
 	return divergences, resp, nil
 }
+
+// ListRepoEffectiveLabels lists the labels usable in a repository, including labels inherited from parent spaces
+func (s *RepositoriesService) ListRepoEffectiveLabels(ctx context.Context, repoPath string, opt *ListOptions) ([]*Label, *Response, error) {
+	path := fmt.Sprintf("repos/%s/labels", url.PathEscape(repoPath))
+	req := s.client.newRequest(ctx)
+
+	s.client.buildQueryParams(req, opt)
+	req.SetQueryParam("inherited", "true")
+
+	var labels []*Label
+	req.SetSuccessResult(&labels)
+
+	fullURL := s.client.buildFullURL(path)
+	resp, err := req.Get(fullURL)
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	response := &Response{Response: resp}
+	s.client.parsePaginationHeaders(response)
+
+	return labels, response, nil
+}
+
+// RepoPullReqSummary represents pull request counts of a repository
+type RepoPullReqSummary struct {
+	OpenCount   *int `json:"open_count,omitempty"`
+	ClosedCount *int `json:"closed_count,omitempty"`
+	MergedCount *int `json:"merged_count,omitempty"`
+}
+
+// RepoSummary represents aggregated statistics of a repository.
+// Gitness computes commit, branch, tag and pull request counts; language
+// breakdown and contributor statistics are not exposed by the API.
+type RepoSummary struct {
+	DefaultBranchCommitCount *int                `json:"default_branch_commit_count,omitempty"`
+	BranchCount              *int                `json:"branch_count,omitempty"`
+	TagCount                 *int                `json:"tag_count,omitempty"`
+	PullReqSummary           *RepoPullReqSummary `json:"pull_req_summary,omitempty"`
+}
+
+// GetRepositorySummary retrieves aggregated statistics of a repository
+func (s *RepositoriesService) GetRepositorySummary(ctx context.Context, repoPath string) (*RepoSummary, *Response, error) {
+	path := fmt.Sprintf("repos/%s/summary", url.PathEscape(repoPath))
+	var summary RepoSummary
+	resp, err := s.client.Get(ctx, path, &summary)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &summary, resp, nil
+}
+
+// RefType represents the kind of object a git reference points at
+type RefType string
+
+// Ref type constants
+const (
+	RefTypeBranch RefType = "branch"
+	RefTypeTag    RefType = "tag"
+	RefTypeCommit RefType = "commit"
+)
+
+// ResolvedRef represents a git reference classified and resolved to a commit SHA
+type ResolvedRef struct {
+	Ref  string  `json:"ref"`
+	Type RefType `json:"type"`
+	SHA  string  `json:"sha"`
+}
+
+// ResolveRef classifies an arbitrary git reference as a branch, tag or commit and
+// resolves it to a commit SHA. Tags take precedence over branches, and branches
+// over commit SHAs, matching how git resolves ambiguous names.
+func (s *RepositoriesService) ResolveRef(ctx context.Context, repoPath, ref string) (*ResolvedRef, *Response, error) {
+	tag, resp, err := s.GetTag(ctx, repoPath, ref)
+	switch {
+	case err == nil:
+		resolved := &ResolvedRef{Ref: ref, Type: RefTypeTag}
+		if tag.Commit != nil && tag.Commit.SHA != nil {
+			resolved.SHA = *tag.Commit.SHA
+		} else if tag.SHA != nil && (tag.IsAnnotated == nil || !*tag.IsAnnotated) {
+			// A lightweight tag points at the commit itself
+			resolved.SHA = *tag.SHA
+		} else {
+			return nil, resp, fmt.Errorf("tag %q: server did not report the tagged commit", ref)
+		}
+		return resolved, resp, nil
+	case !errors.Is(err, ErrTagNotFound):
+		return nil, resp, err
+	}
+
+	branch, resp, err := s.GetBranch(ctx, repoPath, ref)
+	switch {
+	case err == nil:
+		resolved := &ResolvedRef{Ref: ref, Type: RefTypeBranch}
+		if branch.SHA != nil {
+			resolved.SHA = *branch.SHA
+		}
+		return resolved, resp, nil
+	case !isNotFound(err):
+		return nil, resp, err
+	}
+
+	commit, resp, err := s.GetCommit(ctx, repoPath, ref)
+	if err != nil {
+		return nil, resp, err
+	}
+	resolved := &ResolvedRef{Ref: ref, Type: RefTypeCommit}
+	if commit.SHA != nil {
+		resolved.SHA = *commit.SHA
+	}
+	return resolved, resp, nil
+}
+
+// ActivityType identifies the kind of a repository activity entry
+type ActivityType string
+
+// ActivityType constants
+const (
+	ActivityTypeCommit      ActivityType = "commit"
+	ActivityTypePullRequest ActivityType = "pull_request"
+	ActivityTypeTag         ActivityType = "tag"
+)
+
+// RepositoryActivity is a single entry of a repository timeline. Exactly one
+// of Commit, PullRequest or Tag is set, according to Type.
+type RepositoryActivity struct {
+	Type        ActivityType
+	Time        *Time
+	Commit      *Commit
+	PullRequest *PullRequest
+	Tag         *Tag
+}
+
+// ListRepositoryActivityOptions specifies options for listing repository activity
+type ListRepositoryActivityOptions struct {
+	// GitRef selects the branch whose commits are included; defaults to the default branch
+	GitRef *string
+	// Limit caps the number of entries returned; defaults to 20
+	Limit *int
+}
+
+// ListRepositoryActivity returns a timeline of recent commits, pull requests
+// and tags, newest first. Gitness has no activity endpoint, so the timeline
+// is assembled from the commit, pull request and tag lists.
+func (s *RepositoriesService) ListRepositoryActivity(ctx context.Context, repoPath string, opt *ListRepositoryActivityOptions) ([]*RepositoryActivity, *Response, error) {
+	limit := 20
+	var gitRef *string
+	if opt != nil {
+		if opt.Limit != nil && *opt.Limit > 0 {
+			limit = *opt.Limit
+		}
+		gitRef = opt.GitRef
+	}
+	listOpts := ListOptions{Page: Ptr(1), Limit: Ptr(limit)}
+
+	var activity []*RepositoryActivity
+
+	commits, resp, err := s.ListCommits(ctx, repoPath, &ListCommitsOptions{ListOptions: listOpts, GitRef: gitRef})
+	if err != nil {
+		return nil, resp, fmt.Errorf("list commits: %w", err)
+	}
+	for _, commit := range commits {
+		entry := &RepositoryActivity{Type: ActivityTypeCommit, Commit: commit}
+		if commit.Committer != nil {
+			entry.Time = commit.Committer.When
+		}
+		activity = append(activity, entry)
+	}
+
+	prOpts := listOpts
+	prOpts.Sort = Ptr("updated")
+	prOpts.Order = Ptr("desc")
+	pullRequests, resp, err := s.client.PullRequests.ListPullRequests(ctx, repoPath, &ListPullRequestsOptions{ListOptions: prOpts})
+	if err != nil {
+		return nil, resp, fmt.Errorf("list pull requests: %w", err)
+	}
+	for _, pr := range pullRequests {
+		entry := &RepositoryActivity{Type: ActivityTypePullRequest, PullRequest: pr, Time: pr.Updated}
+		if entry.Time == nil {
+			entry.Time = pr.Created
+		}
+		activity = append(activity, entry)
+	}
+
+	tags, resp, err := s.ListTags(ctx, repoPath, &ListTagsOptions{ListOptions: listOpts, Sort: Ptr("date"), Order: Ptr("desc")})
+	if err != nil {
+		return nil, resp, fmt.Errorf("list tags: %w", err)
+	}
+	for _, tag := range tags {
+		entry := &RepositoryActivity{Type: ActivityTypeTag, Tag: tag}
+		if tag.Tagger != nil {
+			entry.Time = tag.Tagger.When
+		}
+		activity = append(activity, entry)
+	}
+
+	// Newest first; entries without a timestamp go last
+	sort.SliceStable(activity, func(i, j int) bool {
+		ti, tj := activity[i].Time, activity[j].Time
+		if ti == nil || tj == nil {
+			return ti != nil
+		}
+		return time.Time(*ti).After(time.Time(*tj))
+	})
+
+	if len(activity) > limit {
+		activity = activity[:limit]
+	}
+	return activity, resp, nil
+}
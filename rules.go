@@ -0,0 +1,469 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MergeMethod represents a pull request merge strategy
+type MergeMethod string
+
+// MergeMethod constants
+const (
+	MergeMethodMerge       MergeMethod = "merge"
+	MergeMethodSquash      MergeMethod = "squash"
+	MergeMethodRebase      MergeMethod = "rebase"
+	MergeMethodFastForward MergeMethod = "fast-forward"
+)
+
+// allMergeMethods lists every merge method, in the order the Gitness UI offers them
+var allMergeMethods = []MergeMethod{MergeMethodMerge, MergeMethodSquash, MergeMethodRebase, MergeMethodFastForward}
+
+// RuleState represents whether a protection rule is enforced
+type RuleState string
+
+// RuleState constants
+const (
+	RuleStateActive   RuleState = "active"
+	RuleStateMonitor  RuleState = "monitor"
+	RuleStateDisabled RuleState = "disabled"
+)
+
+// RuleType represents what a protection rule applies to
+type RuleType string
+
+// RuleType constants
+const (
+	RuleTypeBranch RuleType = "branch"
+	RuleTypeTag    RuleType = "tag"
+	RuleTypePush   RuleType = "push"
+)
+
+// RulePattern selects the refs a rule applies to
+type RulePattern struct {
+	Default *bool    `json:"default,omitempty"`
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Matches reports whether the pattern selects ref, given the repository's
+// default branch. Without Default or Include every ref matches.
+func (p *RulePattern) Matches(ref, defaultBranch string) bool {
+	if p == nil {
+		return true
+	}
+	isDefault := p.Default != nil && *p.Default
+	matches := !isDefault && len(p.Include) == 0
+	if !matches && isDefault && ref == defaultBranch {
+		matches = true
+	}
+	for _, include := range p.Include {
+		if matches {
+			break
+		}
+		matches = globMatch(include, ref)
+	}
+	if !matches {
+		return false
+	}
+	for _, exclude := range p.Exclude {
+		if globMatch(exclude, ref) {
+			return false
+		}
+	}
+	return true
+}
+
+// globMatch reports whether name matches pattern with the doublestar glob
+// syntax Gitness uses for rule patterns: "*", "?" and "[...]" match within a
+// path segment, a "**" segment matches any number of segments, and "{a,b}"
+// matches either alternative. A malformed pattern matches nothing.
+func globMatch(pattern, name string) bool {
+	for _, alternative := range expandBraces(pattern) {
+		if matchSegments(strings.Split(alternative, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for len(pattern) > 1 && pattern[1] == "**" {
+				pattern = pattern[1:]
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// expandBraces expands the "{a,b}" alternatives of a glob pattern
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	depth := 0
+	var options []string
+	last := start + 1
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				options = append(options, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				options = append(options, pattern[last:i])
+				var expanded []string
+				for _, option := range options {
+					expanded = append(expanded, expandBraces(pattern[:start]+option+pattern[i+1:])...)
+				}
+				return expanded
+			}
+		}
+	}
+	// An unclosed brace is matched literally
+	return []string{pattern}
+}
+
+// RuleMergeDefinition restricts how pull requests are merged
+type RuleMergeDefinition struct {
+	Block             *bool         `json:"block,omitempty"`
+	DeleteBranch      *bool         `json:"delete_branch,omitempty"`
+	StrategiesAllowed []MergeMethod `json:"strategies_allowed,omitempty"`
+}
+
+// RuleApprovalsDefinition sets the reviews a pull request needs before merging
+type RuleApprovalsDefinition struct {
+	RequireCodeOwners                  *bool `json:"require_code_owners,omitempty"`
+	RequireLatestCommit                *bool `json:"require_latest_commit,omitempty"`
+	RequireMinimumCount                *int  `json:"require_minimum_count,omitempty"`
+	RequireMinimumDefaultReviewerCount *int  `json:"require_minimum_default_reviewer_count,omitempty"`
+	RequireNoChangeRequest             *bool `json:"require_no_change_request,omitempty"`
+}
+
+// RulePullReqDefinition holds the pull request part of a branch rule
+type RulePullReqDefinition struct {
+	Approvals *RuleApprovalsDefinition `json:"approvals,omitempty"`
+	Merge     *RuleMergeDefinition     `json:"merge,omitempty"`
+}
+
+// RuleDefinition holds the settings of a rule. Only the parts used by the
+// client are modelled.
+type RuleDefinition struct {
+	PullReq *RulePullReqDefinition `json:"pullreq,omitempty"`
+}
+
+// Rule represents a repository protection rule
+type Rule struct {
+	Identifier  *string         `json:"identifier,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Type        *RuleType       `json:"type,omitempty"`
+	State       *RuleState      `json:"state,omitempty"`
+	Pattern     *RulePattern    `json:"pattern,omitempty"`
+	Definition  *RuleDefinition `json:"definition,omitempty"`
+	Scope       *int64          `json:"scope,omitempty"`
+	Created     *int64          `json:"created,omitempty"`
+	Updated     *int64          `json:"updated,omitempty"`
+}
+
+// IsActive reports whether the rule is enforced
+func (r *Rule) IsActive() bool {
+	return r.State != nil && *r.State == RuleStateActive
+}
+
+// ListRulesOptions specifies options for listing repository rules
+type ListRulesOptions struct {
+	ListOptions
+	Type *RuleType `url:"type,omitempty"`
+	// Inherited includes rules defined on parent spaces
+	Inherited *bool `url:"inherited,omitempty"`
+}
+
+// ListRules lists the protection rules of a repository
+func (s *RepositoriesService) ListRules(ctx context.Context, repoPath string, opt *ListRulesOptions) ([]*Rule, *Response, error) {
+	if opt == nil {
+		opt = &ListRulesOptions{}
+	}
+
+	path := fmt.Sprintf("repos/%s/rules", url.PathEscape(repoPath))
+	req := s.client.newRequest(ctx)
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.Type != nil {
+		req.SetQueryParam("type", string(*opt.Type))
+	}
+	if opt.Inherited != nil {
+		req.SetQueryParam("inherited", strconv.FormatBool(*opt.Inherited))
+	}
+
+	var rules []*Rule
+	req.SetSuccessResult(&rules)
+
+	resp, err := req.Get(s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	response := &Response{Response: resp}
+	s.client.parsePaginationHeaders(response)
+	return rules, response, nil
+}
+
+// activeRules returns the active rules of ruleType, including rules inherited
+// from parent spaces, whose pattern matches ref. Branch patterns can select
+// the default branch, so it is looked up for branch rules; an empty ref
+// stands for the default branch.
+func (s *RepositoriesService) activeRules(ctx context.Context, repoPath string, ruleType RuleType, ref string) ([]*Rule, *Response, error) {
+	var defaultBranch string
+	if ruleType == RuleTypeBranch {
+		repo, resp, err := s.GetRepository(ctx, repoPath)
+		if err != nil {
+			return nil, resp, err
+		}
+		if repo.DefaultBranch != nil {
+			defaultBranch = *repo.DefaultBranch
+		}
+		if ref == "" {
+			ref = defaultBranch
+		}
+	}
+
+	rules, resp, err := listAll(ctx, func(ctx context.Context, page int) ([]*Rule, *Response, error) {
+		return s.ListRules(ctx, repoPath, &ListRulesOptions{
+			ListOptions: ListOptions{Page: Ptr(page), Limit: Ptr(100)},
+			Type:        Ptr(ruleType),
+			Inherited:   Ptr(true),
+		})
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var matching []*Rule
+	for _, rule := range rules {
+		if rule.IsActive() && rule.Pattern.Matches(ref, defaultBranch) {
+			matching = append(matching, rule)
+		}
+	}
+	return matching, resp, nil
+}
+
+// GetAllowedMergeMethods returns the merge methods permitted for pull requests
+// targeting the repository's default branch. Repository settings do not
+// carry merge methods; they are restricted by active branch rules, including
+// rules inherited from parent spaces, so the result is the intersection of
+// their allowed strategies. Without restrictions every method is returned.
+func (s *RepositoriesService) GetAllowedMergeMethods(ctx context.Context, repoPath string) ([]MergeMethod, *Response, error) {
+	rules, resp, err := s.activeRules(ctx, repoPath, RuleTypeBranch, "")
+	if err != nil {
+		return nil, resp, err
+	}
+
+	allowed := make(map[MergeMethod]bool, len(allMergeMethods))
+	for _, method := range allMergeMethods {
+		allowed[method] = true
+	}
+	for _, rule := range rules {
+		if rule.Definition == nil || rule.Definition.PullReq == nil || rule.Definition.PullReq.Merge == nil {
+			continue
+		}
+		merge := rule.Definition.PullReq.Merge
+		if merge.Block != nil && *merge.Block {
+			return []MergeMethod{}, resp, nil
+		}
+		if len(merge.StrategiesAllowed) == 0 {
+			continue
+		}
+		permitted := make(map[MergeMethod]bool, len(merge.StrategiesAllowed))
+		for _, method := range merge.StrategiesAllowed {
+			permitted[method] = true
+		}
+		for method := range allowed {
+			if !permitted[method] {
+				delete(allowed, method)
+			}
+		}
+	}
+
+	methods := []MergeMethod{}
+	for _, method := range allMergeMethods {
+		if allowed[method] {
+			methods = append(methods, method)
+		}
+	}
+	return methods, resp, nil
+}
+
+// RuleAction is a ref operation that protection rules can block
+type RuleAction string
+
+// RuleAction constants
+const (
+	RuleActionCreateBranch RuleAction = "create_branch"
+	RuleActionDeleteBranch RuleAction = "delete_branch"
+	RuleActionCreateTag    RuleAction = "create_tag"
+	RuleActionDeleteTag    RuleAction = "delete_tag"
+)
+
+// CheckRulesOptions specifies the operation to evaluate with CheckRules
+type CheckRulesOptions struct {
+	// Ref is the branch or tag name, without the refs/heads/ or refs/tags/ prefix
+	Ref    *string     `json:"ref,omitempty"`
+	Action *RuleAction `json:"action,omitempty"`
+	// Target is the commit or ref a created branch or tag would point to;
+	// the server uses the default branch when it is empty
+	Target *string `json:"target,omitempty"`
+}
+
+// RuleCheckResult reports how protection rules treat an operation
+type RuleCheckResult struct {
+	// Rules are the active rules whose pattern matches the ref
+	Rules []*Rule `json:"rules,omitempty"`
+	// Violations are the rule violations the server reported for the operation
+	Violations []*RuleViolation `json:"violations,omitempty"`
+	// Blocked is true when a violation would reject the operation
+	Blocked bool `json:"blocked"`
+	// Bypassable is true when every blocking violation can be bypassed by the caller
+	Bypassable bool `json:"bypassable"`
+}
+
+// CheckRules evaluates an operation on a ref against the repository's
+// protection rules without performing it. The operation is sent with
+// dry_run_rules, so the server reports violations but changes nothing.
+func (s *RepositoriesService) CheckRules(ctx context.Context, repoPath string, opt *CheckRulesOptions) (*RuleCheckResult, *Response, error) {
+	if opt == nil || opt.Ref == nil || *opt.Ref == "" || opt.Action == nil {
+		return nil, nil, fmt.Errorf("check rules: ref and action are required")
+	}
+	ref := *opt.Ref
+
+	var (
+		method, path string
+		ruleType     RuleType
+		body         any
+	)
+	switch *opt.Action {
+	case RuleActionCreateBranch:
+		method, path, ruleType = http.MethodPost, fmt.Sprintf("repos/%s/branches", url.PathEscape(repoPath)), RuleTypeBranch
+		body = &struct {
+			CreateBranchOptions
+			DryRunRules bool `json:"dry_run_rules"`
+		}{CreateBranchOptions{Name: opt.Ref, Target: opt.Target}, true}
+	case RuleActionDeleteBranch:
+		method, path, ruleType = http.MethodDelete, fmt.Sprintf("repos/%s/branches/%s", url.PathEscape(repoPath), url.PathEscape(ref)), RuleTypeBranch
+	case RuleActionCreateTag:
+		method, path, ruleType = http.MethodPost, fmt.Sprintf("repos/%s/tags", url.PathEscape(repoPath)), RuleTypeTag
+		body = &CreateTagOptions{Name: opt.Ref, Target: opt.Target, DryRunRules: Ptr(true)}
+	case RuleActionDeleteTag:
+		method, path, ruleType = http.MethodDelete, fmt.Sprintf("repos/%s/tags/%s", url.PathEscape(repoPath), url.PathEscape(ref)), RuleTypeTag
+	default:
+		return nil, nil, fmt.Errorf("check rules: unsupported action %q", *opt.Action)
+	}
+
+	rules, resp, err := s.activeRules(ctx, repoPath, ruleType, ref)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	result := &RuleCheckResult{Rules: rules}
+	result.Violations, resp, err = s.dryRunRules(ctx, method, path, body)
+	if err != nil {
+		return nil, resp, err
+	}
+	result.evaluate()
+	return result, resp, nil
+}
+
+// evaluate sets Blocked and Bypassable from the reported violations
+func (r *RuleCheckResult) evaluate() {
+	r.Blocked, r.Bypassable = false, true
+	for _, violation := range r.Violations {
+		if len(violation.Violations) == 0 || (violation.Bypassed != nil && *violation.Bypassed) {
+			continue
+		}
+		r.Blocked = true
+		if violation.Bypassable == nil || !*violation.Bypassable {
+			r.Bypassable = false
+		}
+	}
+	if !r.Blocked {
+		r.Bypassable = false
+	}
+}
+
+// dryRunRules sends a ref operation with dry_run_rules set and returns the
+// reported violations. Violations come back in the success body or, when
+// the server rejects the operation, in a 422 body.
+func (s *RepositoriesService) dryRunRules(ctx context.Context, method, path string, body any) ([]*RuleViolation, *Response, error) {
+	req := s.client.newRequest(ctx)
+	if body != nil {
+		req.SetBodyJsonMarshal(body)
+	} else {
+		req.SetQueryParam("dry_run_rules", "true")
+	}
+
+	resp, err := req.Send(method, s.client.buildFullURL(path))
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+	response := &Response{Response: resp}
+
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		var rejected struct {
+			Violations []*RuleViolation `json:"violations,omitempty"`
+		}
+		if err := json.Unmarshal(resp.Bytes(), &rejected); err == nil && rejected.Violations != nil {
+			return rejected.Violations, response, nil
+		}
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, response, err
+	}
+
+	var output struct {
+		RuleViolations []*RuleViolation `json:"rule_violations,omitempty"`
+	}
+	if len(resp.Bytes()) > 0 {
+		if err := json.Unmarshal(resp.Bytes(), &output); err != nil {
+			return nil, response, fmt.Errorf("decode rule violations: %w", err)
+		}
+	}
+	return output.RuleViolations, response, nil
+}
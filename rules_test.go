@@ -0,0 +1,164 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// TestRulePatternMatches tests default branch, include and exclude matching
+func TestRulePatternMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern *RulePattern
+		ref     string
+		want    bool
+	}{
+		{"nil pattern", nil, "feature", true},
+		{"empty pattern", &RulePattern{}, "feature", true},
+		{"default only", &RulePattern{Default: Ptr(true)}, "main", true},
+		{"default only, other branch", &RulePattern{Default: Ptr(true)}, "feature", false},
+		{"include glob", &RulePattern{Include: []string{"release/*"}}, "release/1.0", true},
+		{"include glob miss", &RulePattern{Include: []string{"release/*"}}, "main", false},
+		{"excluded", &RulePattern{Exclude: []string{"main"}}, "main", false},
+		{"star stays in segment", &RulePattern{Include: []string{"release/*"}}, "release/1.0/hotfix", false},
+		{"doublestar nested", &RulePattern{Include: []string{"release/**"}}, "release/1.0/hotfix", true},
+		{"doublestar zero segments", &RulePattern{Include: []string{"release/**/hotfix"}}, "release/hotfix", true},
+		{"doublestar prefix", &RulePattern{Include: []string{"**/hotfix"}}, "team/release/hotfix", true},
+		{"doublestar miss", &RulePattern{Include: []string{"release/**"}}, "feature/release", false},
+		{"braces", &RulePattern{Include: []string{"{main,develop}"}}, "develop", true},
+		{"nested exclude", &RulePattern{Include: []string{"**"}, Exclude: []string{"users/**"}}, "users/alice/wip", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pattern.Matches(tt.ref, "main"); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGetAllowedMergeMethods tests intersecting strategies of active rules matching the default branch
+func TestGetAllowedMergeMethods(t *testing.T) {
+	rules := `[
+		{"identifier": "default", "type": "branch", "state": "active", "pattern": {"default": true},
+		 "definition": {"pullreq": {"merge": {"strategies_allowed": ["merge", "squash", "rebase"]}}}},
+		{"identifier": "all", "type": "branch", "state": "active",
+		 "definition": {"pullreq": {"merge": {"strategies_allowed": ["squash", "rebase", "fast-forward"]}}}},
+		{"identifier": "monitored", "type": "branch", "state": "monitor",
+		 "definition": {"pullreq": {"merge": {"strategies_allowed": ["merge"]}}}},
+		{"identifier": "release", "type": "branch", "state": "active", "pattern": {"include": ["release/*"]},
+		 "definition": {"pullreq": {"merge": {"strategies_allowed": ["merge"]}}}}
+	]`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path": "space/repo", "default_branch": "main"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/rules", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "branch" || r.URL.Query().Get("inherited") != "true" {
+			t.Errorf("Expected inherited branch rules to be requested, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(rules))
+	})
+
+	client := NewTestClient(mux)
+
+	methods, _, err := client.Repositories.GetAllowedMergeMethods(context.Background(), "space/repo")
+	if err != nil {
+		t.Fatalf("GetAllowedMergeMethods returned error: %v", err)
+	}
+	if want := []MergeMethod{MergeMethodSquash, MergeMethodRebase}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("Expected %v, got %v", want, methods)
+	}
+}
+
+// TestCheckRules tests dry-run evaluation of branch and tag operations
+func TestCheckRules(t *testing.T) {
+	var dryRunBodies []map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"default_branch": "main"}`))
+	})
+	mux.HandleFunc("GET /api/v1/repos/{repo}/rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("type") == "tag" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"identifier": "protect-main", "state": "active", "pattern": {"default": true}},
+			{"identifier": "releases", "state": "active", "pattern": {"include": ["release/*"]}},
+			{"identifier": "old", "state": "disabled"},
+			{"identifier": "watch", "state": "monitor"}
+		]`))
+	})
+	mux.HandleFunc("DELETE /api/v1/repos/{repo}/branches/{branch}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dry_run_rules") != "true" {
+			t.Errorf("Expected dry_run_rules=true, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "blocked", "violations": [
+			{"rule": {"identifier": "protect-main"}, "bypassable": false,
+			 "violations": [{"code": "lifecycle.delete", "message": "Deleting the branch is not allowed"}]}
+		]}`))
+	})
+	mux.HandleFunc("POST /api/v1/repos/{repo}/tags", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		dryRunBodies = append(dryRunBodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"dry_run_rules": true, "rule_violations": []}`))
+	})
+
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	result, _, err := client.Repositories.CheckRules(ctx, "space/repo", &CheckRulesOptions{
+		Ref:    Ptr("main"),
+		Action: Ptr(RuleActionDeleteBranch),
+	})
+	if err != nil {
+		t.Fatalf("CheckRules returned error: %v", err)
+	}
+	if !result.Blocked || result.Bypassable {
+		t.Errorf("Expected a non-bypassable block, got %+v", result)
+	}
+	if len(result.Rules) != 1 || *result.Rules[0].Identifier != "protect-main" {
+		t.Errorf("Expected only protect-main to apply, got %d rules", len(result.Rules))
+	}
+	if len(result.Violations) != 1 || *result.Violations[0].Violations[0].Code != "lifecycle.delete" {
+		t.Errorf("Unexpected violations %+v", result.Violations)
+	}
+
+	result, _, err = client.Repositories.CheckRules(ctx, "space/repo", &CheckRulesOptions{
+		Ref:    Ptr("v1.0.0"),
+		Action: Ptr(RuleActionCreateTag),
+	})
+	if err != nil {
+		t.Fatalf("CheckRules returned error: %v", err)
+	}
+	if result.Blocked || len(result.Rules) != 0 {
+		t.Errorf("Expected tag creation to be allowed, got %+v", result)
+	}
+	if len(dryRunBodies) != 1 || dryRunBodies[0]["dry_run_rules"] != true || dryRunBodies[0]["name"] != "v1.0.0" {
+		t.Errorf("Expected a dry-run tag request, got %v", dryRunBodies)
+	}
+
+	if _, _, err := client.Repositories.CheckRules(ctx, "space/repo", &CheckRulesOptions{Ref: Ptr("main")}); err == nil {
+		t.Error("Expected error without an action")
+	}
+}
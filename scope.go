@@ -0,0 +1,321 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"strings"
+)
+
+// SpaceClient is a view of a Client bound to a single space. Its methods
+// delegate to the regular services with the space reference filled in.
+type SpaceClient struct {
+	client   *Client
+	spaceRef string
+}
+
+// InSpace returns a SpaceClient for space-scoped calls within spaceRef
+func (c *Client) InSpace(spaceRef string) *SpaceClient {
+	return &SpaceClient{client: c, spaceRef: strings.Trim(spaceRef, "/")}
+}
+
+// Ref returns the space reference the client is bound to
+func (sc *SpaceClient) Ref() string {
+	return sc.spaceRef
+}
+
+// RepoPath returns the full path of the repository named repoName in the space
+func (sc *SpaceClient) RepoPath(repoName string) string {
+	return sc.spaceRef + "/" + repoName
+}
+
+// resourceRef returns the reference of a space-level resource such as a secret
+func (sc *SpaceClient) resourceRef(identifier string) string {
+	return sc.spaceRef + "/" + identifier
+}
+
+// GetSpace retrieves the space
+func (sc *SpaceClient) GetSpace(ctx context.Context) (*Space, *Response, error) {
+	return sc.client.Spaces.GetSpace(ctx, sc.spaceRef)
+}
+
+// ListRepositories lists repositories in the space
+func (sc *SpaceClient) ListRepositories(ctx context.Context, opt *ListRepositoriesOptions) ([]*Repository, *Response, error) {
+	return sc.client.Spaces.ListRepositories(ctx, sc.spaceRef, opt)
+}
+
+// GetRepository retrieves the repository named repoName in the space
+func (sc *SpaceClient) GetRepository(ctx context.Context, repoName string) (*Repository, *Response, error) {
+	return sc.client.Repositories.GetRepository(ctx, sc.RepoPath(repoName))
+}
+
+// CreateRepository creates a repository in the space
+func (sc *SpaceClient) CreateRepository(ctx context.Context, opt *CreateRepositoryOptions) (*Repository, *Response, error) {
+	return sc.client.Repositories.CreateRepository(ctx, sc.spaceRef, opt)
+}
+
+// CreateRepositoryFromTemplate creates a repository in the space from a template repository
+func (sc *SpaceClient) CreateRepositoryFromTemplate(ctx context.Context, opt *CreateRepositoryFromTemplateOptions) (*Repository, *Response, error) {
+	return sc.client.Repositories.CreateRepositoryFromTemplate(ctx, sc.spaceRef, opt)
+}
+
+// ImportRepository imports a repository into the space
+func (sc *SpaceClient) ImportRepository(ctx context.Context, opt *ImportRepositoryOptions) (*Repository, *Response, error) {
+	return sc.client.Repositories.ImportRepository(ctx, sc.spaceRef, opt)
+}
+
+// ListPrincipals lists the members and service accounts of the space
+func (sc *SpaceClient) ListPrincipals(ctx context.Context, opt *ListSpacePrincipalsOptions) ([]*SpacePrincipal, *Response, error) {
+	return sc.client.Spaces.ListSpacePrincipals(ctx, sc.spaceRef, opt)
+}
+
+// ListSecrets lists the secrets of the space
+func (sc *SpaceClient) ListSecrets(ctx context.Context, opt *ListOptions) ([]*Secret, *Response, error) {
+	return sc.client.Secrets.ListSpaceSecrets(ctx, sc.spaceRef, opt)
+}
+
+// CreateSecret creates a secret in the space
+func (sc *SpaceClient) CreateSecret(ctx context.Context, opt *CreateSecretOptions) (*Secret, *Response, error) {
+	return sc.client.Secrets.CreateSpaceSecret(ctx, sc.spaceRef, opt)
+}
+
+// GetSecret retrieves a secret of the space by identifier
+func (sc *SpaceClient) GetSecret(ctx context.Context, identifier string) (*Secret, *Response, error) {
+	return sc.client.Secrets.GetSecret(ctx, sc.resourceRef(identifier))
+}
+
+// UpdateSecret updates a secret of the space by identifier
+func (sc *SpaceClient) UpdateSecret(ctx context.Context, identifier string, opt *CreateSecretOptions) (*Secret, *Response, error) {
+	return sc.client.Secrets.UpdateSecret(ctx, sc.resourceRef(identifier), opt)
+}
+
+// DeleteSecret deletes a secret of the space by identifier
+func (sc *SpaceClient) DeleteSecret(ctx context.Context, identifier string) (*Response, error) {
+	return sc.client.Secrets.DeleteSecret(ctx, sc.resourceRef(identifier))
+}
+
+// ListTemplates lists the templates of the space
+func (sc *SpaceClient) ListTemplates(ctx context.Context, opt *ListOptions) ([]*Template, *Response, error) {
+	return sc.client.Templates.ListTemplates(ctx, sc.spaceRef, opt)
+}
+
+// CreateTemplate creates a template in the space
+func (sc *SpaceClient) CreateTemplate(ctx context.Context, opt *CreateTemplateOptions) (*Template, *Response, error) {
+	return sc.client.Templates.CreateTemplate(ctx, sc.spaceRef, opt)
+}
+
+// GetTemplate retrieves a template of the space
+func (sc *SpaceClient) GetTemplate(ctx context.Context, identifier string) (*Template, *Response, error) {
+	return sc.client.Templates.GetTemplate(ctx, sc.spaceRef, identifier)
+}
+
+// UpdateTemplate updates a template of the space
+func (sc *SpaceClient) UpdateTemplate(ctx context.Context, identifier string, opt *UpdateTemplateOptions) (*Template, *Response, error) {
+	return sc.client.Templates.UpdateTemplate(ctx, sc.spaceRef, identifier, opt)
+}
+
+// DeleteTemplate deletes a template of the space
+func (sc *SpaceClient) DeleteTemplate(ctx context.Context, identifier string) (*Response, error) {
+	return sc.client.Templates.DeleteTemplate(ctx, sc.spaceRef, identifier)
+}
+
+// ResolveTemplate renders a template of the space with inputs
+func (sc *SpaceClient) ResolveTemplate(ctx context.Context, identifier string, inputs map[string]any) (string, *Response, error) {
+	return sc.client.Templates.ResolveTemplate(ctx, sc.spaceRef, identifier, inputs)
+}
+
+// ListRepoWebhooks lists the webhooks of the repository named repoName in the space
+func (sc *SpaceClient) ListRepoWebhooks(ctx context.Context, repoName string, opt *ListOptions) ([]*Webhook, *Response, error) {
+	return sc.client.Webhooks.ListWebhooks(ctx, sc.RepoPath(repoName), opt)
+}
+
+// CreateRepoWebhook creates a webhook on the repository named repoName in the space
+func (sc *SpaceClient) CreateRepoWebhook(ctx context.Context, repoName string, opt *CreateWebhookOptions) (*Webhook, *Response, error) {
+	return sc.client.Webhooks.CreateWebhook(ctx, sc.RepoPath(repoName), opt)
+}
+
+// UpdateRepoWebhook updates a webhook on the repository named repoName in the space
+func (sc *SpaceClient) UpdateRepoWebhook(ctx context.Context, repoName, webhookIdentifier string, opt *UpdateWebhookOptions) (*Webhook, *Response, error) {
+	return sc.client.Webhooks.UpdateWebhook(ctx, sc.RepoPath(repoName), webhookIdentifier, opt)
+}
+
+// RepoClient is a view of a Client bound to a single repository. Its methods
+// delegate to the regular services with the repository path filled in.
+type RepoClient struct {
+	client   *Client
+	repoPath string
+}
+
+// Repo returns a RepoClient for calls on the repository at repoPath
+func (c *Client) Repo(repoPath string) *RepoClient {
+	return &RepoClient{client: c, repoPath: strings.Trim(repoPath, "/")}
+}
+
+// Repo returns a RepoClient for the repository named repoName in the space
+func (sc *SpaceClient) Repo(repoName string) *RepoClient {
+	return sc.client.Repo(sc.RepoPath(repoName))
+}
+
+// Path returns the repository path the client is bound to
+func (rc *RepoClient) Path() string {
+	return rc.repoPath
+}
+
+// Get retrieves the repository
+func (rc *RepoClient) Get(ctx context.Context) (*Repository, *Response, error) {
+	return rc.client.Repositories.GetRepository(ctx, rc.repoPath)
+}
+
+// Update updates the repository
+func (rc *RepoClient) Update(ctx context.Context, opt *UpdateRepositoryOptions) (*Repository, *Response, error) {
+	return rc.client.Repositories.UpdateRepository(ctx, rc.repoPath, opt)
+}
+
+// Delete deletes the repository
+func (rc *RepoClient) Delete(ctx context.Context, deleteID *string) (*Response, error) {
+	return rc.client.Repositories.DeleteRepository(ctx, rc.repoPath, deleteID)
+}
+
+// ListBranches lists the branches of the repository
+func (rc *RepoClient) ListBranches(ctx context.Context, opt *ListOptions) ([]*Branch, *Response, error) {
+	return rc.client.Repositories.ListBranches(ctx, rc.repoPath, opt)
+}
+
+// GetBranch retrieves a branch of the repository
+func (rc *RepoClient) GetBranch(ctx context.Context, branchName string) (*Branch, *Response, error) {
+	return rc.client.Repositories.GetBranch(ctx, rc.repoPath, branchName)
+}
+
+// CreateBranch creates a branch in the repository
+func (rc *RepoClient) CreateBranch(ctx context.Context, opt *CreateBranchOptions) (*Branch, *Response, error) {
+	return rc.client.Repositories.CreateBranch(ctx, rc.repoPath, opt)
+}
+
+// DeleteBranch deletes a branch of the repository
+func (rc *RepoClient) DeleteBranch(ctx context.Context, branchName string) (*Response, error) {
+	return rc.client.Repositories.DeleteBranch(ctx, rc.repoPath, branchName)
+}
+
+// ListCommits lists the commits of the repository
+func (rc *RepoClient) ListCommits(ctx context.Context, opt *ListCommitsOptions) ([]*Commit, *Response, error) {
+	return rc.client.Repositories.ListCommits(ctx, rc.repoPath, opt)
+}
+
+// StreamCommits calls fn for each commit of the repository
+func (rc *RepoClient) StreamCommits(ctx context.Context, opt *ListCommitsOptions, fn func(*Commit) error) (*Response, error) {
+	return rc.client.Repositories.StreamCommits(ctx, rc.repoPath, opt, fn)
+}
+
+// ListActivity returns a timeline of recent commits, pull requests and tags of the repository
+func (rc *RepoClient) ListActivity(ctx context.Context, opt *ListRepositoryActivityOptions) ([]*RepositoryActivity, *Response, error) {
+	return rc.client.Repositories.ListRepositoryActivity(ctx, rc.repoPath, opt)
+}
+
+// GetCommit retrieves a commit of the repository
+func (rc *RepoClient) GetCommit(ctx context.Context, commitSHA string) (*Commit, *Response, error) {
+	return rc.client.Repositories.GetCommit(ctx, rc.repoPath, commitSHA)
+}
+
+// GetCommitWithOptions retrieves a commit of the repository with options
+func (rc *RepoClient) GetCommitWithOptions(ctx context.Context, commitSHA string, opt *GetCommitOptions) (*Commit, *Response, error) {
+	return rc.client.Repositories.GetCommitWithOptions(ctx, rc.repoPath, commitSHA, opt)
+}
+
+// GetCommitDiff retrieves the raw diff of a commit
+func (rc *RepoClient) GetCommitDiff(ctx context.Context, commitSHA string, opt *GetCommitDiffOptions) (string, *Response, error) {
+	return rc.client.Repositories.GetCommitDiff(ctx, rc.repoPath, commitSHA, opt)
+}
+
+// GetDiff retrieves the diff between two refs
+func (rc *RepoClient) GetDiff(ctx context.Context, base, head string, opt *GetDiffOptions) (*Diff, *Response, error) {
+	return rc.client.Repositories.GetDiff(ctx, rc.repoPath, base, head, opt)
+}
+
+// CompareRefs retrieves diff statistics between two refs
+func (rc *RepoClient) CompareRefs(ctx context.Context, base, head string) (*DiffStats, *Response, error) {
+	return rc.client.Repositories.CompareRefs(ctx, rc.repoPath, base, head)
+}
+
+// ListPullRequests lists the pull requests of the repository
+func (rc *RepoClient) ListPullRequests(ctx context.Context, opt *ListPullRequestsOptions) ([]*PullRequest, *Response, error) {
+	return rc.client.PullRequests.ListPullRequests(ctx, rc.repoPath, opt)
+}
+
+// GetPullRequest retrieves a pull request of the repository
+func (rc *RepoClient) GetPullRequest(ctx context.Context, pullRequestNumber int64) (*PullRequest, *Response, error) {
+	return rc.client.PullRequests.GetPullRequest(ctx, rc.repoPath, pullRequestNumber)
+}
+
+// CreatePullRequest creates a pull request in the repository
+func (rc *RepoClient) CreatePullRequest(ctx context.Context, opt *CreatePullRequestOptions) (*PullRequest, *Response, error) {
+	return rc.client.PullRequests.CreatePullRequest(ctx, rc.repoPath, opt)
+}
+
+// UpdatePullRequest updates a pull request of the repository
+func (rc *RepoClient) UpdatePullRequest(ctx context.Context, pullRequestNumber int64, opt *UpdatePullRequestOptions) (*PullRequest, *Response, error) {
+	return rc.client.PullRequests.UpdatePullRequest(ctx, rc.repoPath, pullRequestNumber, opt)
+}
+
+// MergePullRequest merges a pull request of the repository
+func (rc *RepoClient) MergePullRequest(ctx context.Context, pullRequestNumber int64, opt *MergePullRequestOptions) (*PullRequest, *Response, error) {
+	return rc.client.PullRequests.MergePullRequest(ctx, rc.repoPath, pullRequestNumber, opt)
+}
+
+// GetPullRequestSummary returns pull request counts for the repository
+func (rc *RepoClient) GetPullRequestSummary(ctx context.Context, opt *PullRequestSummaryOptions) (*PullRequestSummary, *Response, error) {
+	return rc.client.PullRequests.GetPullRequestSummary(ctx, rc.repoPath, opt)
+}
+
+// ListPipelines lists the pipelines of the repository
+func (rc *RepoClient) ListPipelines(ctx context.Context, opt *ListOptions) ([]*Pipeline, *Response, error) {
+	return rc.client.Pipelines.ListPipelines(ctx, rc.repoPath, opt)
+}
+
+// GetPipeline retrieves a pipeline of the repository
+func (rc *RepoClient) GetPipeline(ctx context.Context, pipelineID string) (*Pipeline, *Response, error) {
+	return rc.client.Pipelines.GetPipeline(ctx, rc.repoPath, pipelineID)
+}
+
+// CreatePipeline creates a pipeline in the repository
+func (rc *RepoClient) CreatePipeline(ctx context.Context, opt *CreatePipelineOptions) (*Pipeline, *Response, error) {
+	return rc.client.Pipelines.CreatePipeline(ctx, rc.repoPath, opt)
+}
+
+// CreateExecution starts a pipeline execution, optionally on branch
+func (rc *RepoClient) CreateExecution(ctx context.Context, pipelineID string, branch *string) (*PipelineExecution, *Response, error) {
+	return rc.client.Pipelines.CreateExecution(ctx, rc.repoPath, pipelineID, branch)
+}
+
+// ListExecutions lists recent executions across the pipelines of the repository
+func (rc *RepoClient) ListExecutions(ctx context.Context, opt *ListRepositoryExecutionsOptions) ([]*PipelineExecution, *Response, error) {
+	return rc.client.Pipelines.ListRepositoryExecutions(ctx, rc.repoPath, opt)
+}
+
+// ListWebhooks lists the webhooks of the repository
+func (rc *RepoClient) ListWebhooks(ctx context.Context, opt *ListOptions) ([]*Webhook, *Response, error) {
+	return rc.client.Webhooks.ListWebhooks(ctx, rc.repoPath, opt)
+}
+
+// CreateWebhook creates a webhook on the repository
+func (rc *RepoClient) CreateWebhook(ctx context.Context, opt *CreateWebhookOptions) (*Webhook, *Response, error) {
+	return rc.client.Webhooks.CreateWebhook(ctx, rc.repoPath, opt)
+}
+
+// UpdateWebhook updates a webhook of the repository
+func (rc *RepoClient) UpdateWebhook(ctx context.Context, webhookIdentifier string, opt *UpdateWebhookOptions) (*Webhook, *Response, error) {
+	return rc.client.Webhooks.UpdateWebhook(ctx, rc.repoPath, webhookIdentifier, opt)
+}
+
+// ListSecrets lists the secrets of the repository
+func (rc *RepoClient) ListSecrets(ctx context.Context, opt *ListOptions) ([]*Secret, *Response, error) {
+	return rc.client.Secrets.ListRepoSecrets(ctx, rc.repoPath, opt)
+}
+
+// CreateSecret creates a secret in the repository
+func (rc *RepoClient) CreateSecret(ctx context.Context, opt *CreateSecretOptions) (*Secret, *Response, error) {
+	return rc.client.Secrets.CreateRepoSecret(ctx, rc.repoPath, opt)
+}
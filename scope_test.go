@@ -0,0 +1,111 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// TestInSpace tests that space-scoped calls are routed to the bound space
+func TestInSpace(t *testing.T) {
+	var paths []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v1/spaces/org%2Fteam/secrets", "/api/v1/spaces/org%2Fteam/templates", "/api/v1/repos/org%2Fteam%2Fapp/webhooks":
+			w.Write([]byte(`[]`))
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"identifier": "x"})
+		}
+	})
+
+	space := NewTestClient(handler).InSpace("/org/team/")
+	if space.Ref() != "org/team" {
+		t.Errorf("Expected ref org/team, got %q", space.Ref())
+	}
+
+	ctx := context.Background()
+	calls := []func() error{
+		func() error { _, _, err := space.GetRepository(ctx, "app"); return err },
+		func() error { _, _, err := space.ListSecrets(ctx, nil); return err },
+		func() error { _, _, err := space.GetSecret(ctx, "token"); return err },
+		func() error { _, _, err := space.ListTemplates(ctx, nil); return err },
+		func() error { _, _, err := space.GetTemplate(ctx, "build"); return err },
+		func() error { _, _, err := space.ListRepoWebhooks(ctx, "app", nil); return err },
+	}
+	for _, call := range calls {
+		if err := call(); err != nil {
+			t.Fatalf("Scoped call returned error: %v", err)
+		}
+	}
+
+	want := []string{
+		"GET /api/v1/repos/org%2Fteam%2Fapp",
+		"GET /api/v1/spaces/org%2Fteam/secrets",
+		"GET /api/v1/secrets/org%2Fteam%2Ftoken",
+		"GET /api/v1/spaces/org%2Fteam/templates",
+		"GET /api/v1/spaces/org%2Fteam/templates/build",
+		"GET /api/v1/repos/org%2Fteam%2Fapp/webhooks",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %d requests, got %v", len(want), paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Request %d: expected %q, got %q", i, want[i], paths[i])
+		}
+	}
+}
+
+// TestRepo tests that repository-scoped calls are routed to the bound repository
+func TestRepo(t *testing.T) {
+	var paths []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.EscapedPath() == "/api/v1/repos/org%2Fapp/branches" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	client := NewTestClient(handler)
+	repo := client.Repo("org/app")
+	if repo.Path() != "org/app" || client.InSpace("org").Repo("app").Path() != "org/app" {
+		t.Errorf("Unexpected repository path %q", repo.Path())
+	}
+
+	ctx := context.Background()
+	if _, _, err := repo.ListBranches(ctx, nil); err != nil {
+		t.Fatalf("ListBranches returned error: %v", err)
+	}
+	if _, _, err := repo.GetPullRequest(ctx, 7); err != nil {
+		t.Fatalf("GetPullRequest returned error: %v", err)
+	}
+	if _, _, err := repo.GetPipeline(ctx, "ci"); err != nil {
+		t.Fatalf("GetPipeline returned error: %v", err)
+	}
+
+	want := []string{
+		"GET /api/v1/repos/org%2Fapp/branches",
+		"GET /api/v1/repos/org%2Fapp/pullreq/7",
+		"GET /api/v1/repos/org%2Fapp/pipelines/ci",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %d requests, got %v", len(want), paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Request %d: expected %q, got %q", i, want[i], paths[i])
+		}
+	}
+}
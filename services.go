@@ -8,8 +8,13 @@ package gitness
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"regexp"
+	"sync"
+	"time"
 )
 
 // GitspacesService handles communication with gitspace related methods
@@ -32,18 +37,78 @@ type WebhooksService struct {
 	client *Client
 }
 
+// WebhookTrigger is an event that fires a webhook
+type WebhookTrigger string
+
+// WebhookTrigger constants
+const (
+	WebhookTriggerArtifactCreated             WebhookTrigger = "artifact_created"
+	WebhookTriggerArtifactDeleted             WebhookTrigger = "artifact_deleted"
+	WebhookTriggerBranchCreated               WebhookTrigger = "branch_created"
+	WebhookTriggerBranchDeleted               WebhookTrigger = "branch_deleted"
+	WebhookTriggerBranchUpdated               WebhookTrigger = "branch_updated"
+	WebhookTriggerPipelineEnded               WebhookTrigger = "pipeline_ended"
+	WebhookTriggerPipelineStarted             WebhookTrigger = "pipeline_started"
+	WebhookTriggerPullReqBranchUpdated        WebhookTrigger = "pullreq_branch_updated"
+	WebhookTriggerPullReqClosed               WebhookTrigger = "pullreq_closed"
+	WebhookTriggerPullReqCommentCreated       WebhookTrigger = "pullreq_comment_created"
+	WebhookTriggerPullReqCommentStatusUpdated WebhookTrigger = "pullreq_comment_status_updated"
+	WebhookTriggerPullReqCommentUpdated       WebhookTrigger = "pullreq_comment_updated"
+	WebhookTriggerPullReqCreated              WebhookTrigger = "pullreq_created"
+	WebhookTriggerPullReqLabelAssigned        WebhookTrigger = "pullreq_label_assigned"
+	WebhookTriggerPullReqMerged               WebhookTrigger = "pullreq_merged"
+	WebhookTriggerPullReqReopened             WebhookTrigger = "pullreq_reopened"
+	WebhookTriggerPullReqReviewSubmitted      WebhookTrigger = "pullreq_review_submitted"
+	WebhookTriggerPullReqTargetBranchChanged  WebhookTrigger = "pullreq_target_branch_changed"
+	WebhookTriggerPullReqUpdated              WebhookTrigger = "pullreq_updated"
+	WebhookTriggerTagCreated                  WebhookTrigger = "tag_created"
+	WebhookTriggerTagDeleted                  WebhookTrigger = "tag_deleted"
+	WebhookTriggerTagUpdated                  WebhookTrigger = "tag_updated"
+)
+
+// IsValid reports whether t is one of the triggers listed above
+func (t WebhookTrigger) IsValid() bool {
+	switch t {
+	case WebhookTriggerArtifactCreated, WebhookTriggerArtifactDeleted,
+		WebhookTriggerBranchCreated, WebhookTriggerBranchDeleted, WebhookTriggerBranchUpdated,
+		WebhookTriggerPipelineEnded, WebhookTriggerPipelineStarted,
+		WebhookTriggerPullReqBranchUpdated, WebhookTriggerPullReqClosed, WebhookTriggerPullReqCommentCreated,
+		WebhookTriggerPullReqCommentStatusUpdated, WebhookTriggerPullReqCommentUpdated, WebhookTriggerPullReqCreated,
+		WebhookTriggerPullReqLabelAssigned, WebhookTriggerPullReqMerged, WebhookTriggerPullReqReopened,
+		WebhookTriggerPullReqReviewSubmitted, WebhookTriggerPullReqTargetBranchChanged, WebhookTriggerPullReqUpdated,
+		WebhookTriggerTagCreated, WebhookTriggerTagDeleted, WebhookTriggerTagUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+// webhookTriggerFormat matches the snake_case form of webhook trigger names
+var webhookTriggerFormat = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// validateWebhookTriggers rejects malformed trigger names. Well-formed names
+// the client does not know are accepted, since newer servers add triggers.
+func validateWebhookTriggers(triggers []WebhookTrigger) error {
+	for _, trigger := range triggers {
+		if !webhookTriggerFormat.MatchString(string(trigger)) {
+			return fmt.Errorf("invalid webhook trigger %q", trigger)
+		}
+	}
+	return nil
+}
+
 // Webhook represents a Gitness webhook
 type Webhook struct {
-	ID          *int64   `json:"id,omitempty"`
-	Identifier  *string  `json:"identifier,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	URL         *string  `json:"url,omitempty"`
-	Secret      *string  `json:"secret,omitempty"`
-	Triggers    []string `json:"triggers,omitempty"`
-	Enabled     *bool    `json:"enabled,omitempty"`
-	Insecure    *bool    `json:"insecure,omitempty"`
-	Created     *Time    `json:"created,omitempty"`
-	Updated     *Time    `json:"updated,omitempty"`
+	ID          *int64           `json:"id,omitempty"`
+	Identifier  *string          `json:"identifier,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	URL         *string          `json:"url,omitempty"`
+	Secret      *string          `json:"secret,omitempty"`
+	Triggers    []WebhookTrigger `json:"triggers,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Insecure    *bool            `json:"insecure,omitempty"`
+	Created     *Time            `json:"created,omitempty"`
+	Updated     *Time            `json:"updated,omitempty"`
 }
 
 // Secret represents a Gitness secret
@@ -57,13 +122,24 @@ type Secret struct {
 
 // CreateWebhookOptions specifies options for creating a webhook
 type CreateWebhookOptions struct {
-	Identifier  *string  `json:"identifier,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	URL         *string  `json:"url,omitempty"`
-	Secret      *string  `json:"secret,omitempty"`
-	Triggers    []string `json:"triggers,omitempty"`
-	Enabled     *bool    `json:"enabled,omitempty"`
-	Insecure    *bool    `json:"insecure,omitempty"`
+	Identifier  *string          `json:"identifier,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	URL         *string          `json:"url,omitempty"`
+	Secret      *string          `json:"secret,omitempty"`
+	Triggers    []WebhookTrigger `json:"triggers,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Insecure    *bool            `json:"insecure,omitempty"`
+}
+
+// UpdateWebhookOptions specifies options for updating a webhook
+type UpdateWebhookOptions struct {
+	Identifier  *string          `json:"identifier,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	URL         *string          `json:"url,omitempty"`
+	Secret      *string          `json:"secret,omitempty"`
+	Triggers    []WebhookTrigger `json:"triggers,omitempty"`
+	Enabled     *bool            `json:"enabled,omitempty"`
+	Insecure    *bool            `json:"insecure,omitempty"`
 }
 
 // CreateSecretOptions specifies options for creating a secret
@@ -75,6 +151,11 @@ type CreateSecretOptions struct {
 
 // CreateWebhook creates a webhook for a repository
 func (s *WebhooksService) CreateWebhook(ctx context.Context, repoPath string, opt *CreateWebhookOptions) (*Webhook, *Response, error) {
+	if opt != nil {
+		if err := validateWebhookTriggers(opt.Triggers); err != nil {
+			return nil, nil, err
+		}
+	}
 	path := fmt.Sprintf("repos/%s/webhooks", url.PathEscape(repoPath))
 	var webhook Webhook
 	resp, err := s.client.Post(ctx, path, opt, &webhook)
@@ -95,6 +176,22 @@ func (s *WebhooksService) ListWebhooks(ctx context.Context, repoPath string, opt
 	return webhooks, resp, nil
 }
 
+// UpdateWebhook updates a repository webhook
+func (s *WebhooksService) UpdateWebhook(ctx context.Context, repoPath, webhookIdentifier string, opt *UpdateWebhookOptions) (*Webhook, *Response, error) {
+	if opt != nil {
+		if err := validateWebhookTriggers(opt.Triggers); err != nil {
+			return nil, nil, err
+		}
+	}
+	path := fmt.Sprintf("repos/%s/webhooks/%s", url.PathEscape(repoPath), url.PathEscape(webhookIdentifier))
+	var webhook Webhook
+	resp, err := s.client.Patch(ctx, path, opt, &webhook)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &webhook, resp, nil
+}
+
 // CreateSecret creates a secret for a repository
 func (s *SecretsService) CreateSecret(ctx context.Context, repoPath string, opt *CreateSecretOptions) (*Secret, *Response, error) {
 	path := fmt.Sprintf("repos/%s/secrets", url.PathEscape(repoPath))
@@ -181,6 +278,11 @@ func (s *SecretsService) GetSecret(ctx context.Context, secretRef string) (*Secr
 	return &secret, resp, nil
 }
 
+// GetSecretOrNil is like GetSecret but returns a nil Secret without an error when the secret does not exist
+func (s *SecretsService) GetSecretOrNil(ctx context.Context, secretRef string) (*Secret, *Response, error) {
+	return notFoundAsNil(s.GetSecret(ctx, secretRef))
+}
+
 // UpdateSecret updates a secret
 func (s *SecretsService) UpdateSecret(ctx context.Context, secretRef string, opt *CreateSecretOptions) (*Secret, *Response, error) {
 	path := fmt.Sprintf("secrets/%s", url.PathEscape(secretRef))
@@ -199,6 +301,93 @@ func (s *SecretsService) DeleteSecret(ctx context.Context, secretRef string) (*R
 	return resp, err
 }
 
+// CopySecretAndDelete recreates a secret under targetSpaceRef with the same
+// identifier and description, then deletes the original. Gitness has no move
+// endpoint and never returns secret values, so the caller passes the value as
+// data. Pipelines refer to secrets by identifier, which keeps resolving when
+// the target space is the pipeline's space or one of its ancestors. If
+// deleting the original fails, the new secret is returned together with the error.
+func (s *SecretsService) CopySecretAndDelete(ctx context.Context, secretRef, targetSpaceRef, data string) (*Secret, *Response, error) {
+	if _, _, err := splitSpaceRef("secret", secretRef); err != nil {
+		return nil, nil, err
+	}
+	secret, resp, err := s.GetSecret(ctx, secretRef)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	copied, resp, err := s.CreateSpaceSecret(ctx, targetSpaceRef, &CreateSecretOptions{
+		Identifier:  secret.Identifier,
+		Description: secret.Description,
+		Data:        Ptr(data),
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp, err := s.DeleteSecret(ctx, secretRef); err != nil {
+		return copied, resp, fmt.Errorf("delete original secret %q: %w", secretRef, err)
+	}
+	return copied, resp, nil
+}
+
+// syncSecretsConcurrency bounds the number of repositories SyncSecretsToRepos updates at once
+const syncSecretsConcurrency = 8
+
+// SyncSecretsToRepos creates or updates each of secrets in every repository
+// of repoPaths, working on several repositories concurrently. Secrets that
+// already exist are updated, which makes it suitable for rotating shared CI
+// secrets. The returned map holds the failures keyed by repository path, with
+// the errors of all failed secrets of a repository joined; it is empty when
+// every secret was synced.
+func (s *SecretsService) SyncSecretsToRepos(ctx context.Context, repoPaths []string, secrets []*CreateSecretOptions) map[string]error {
+	failures := make(map[string]error)
+	var mu sync.Mutex
+	sem := make(chan struct{}, syncSecretsConcurrency)
+
+	var wg sync.WaitGroup
+	for _, repoPath := range repoPaths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var errs []error
+			for _, secret := range secrets {
+				if err := s.upsertRepoSecret(ctx, repoPath, secret); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if err := errors.Join(errs...); err != nil {
+				mu.Lock()
+				failures[repoPath] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return failures
+}
+
+// upsertRepoSecret creates a repository secret, updating it instead when it already exists
+func (s *SecretsService) upsertRepoSecret(ctx context.Context, repoPath string, opt *CreateSecretOptions) error {
+	if opt == nil || opt.Identifier == nil {
+		return errors.New("secret identifier is required")
+	}
+
+	_, _, err := s.CreateRepoSecret(ctx, repoPath, opt)
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusConflict {
+		_, _, err = s.UpdateSecret(ctx, repoPath+"/"+*opt.Identifier, opt)
+	}
+	if err != nil {
+		return fmt.Errorf("secret %q: %w", *opt.Identifier, err)
+	}
+	return nil
+}
+
 // Gitspace represents a Gitness gitspace
 type Gitspace struct {
 	ID                *int64         `json:"id,omitempty"`
@@ -233,11 +422,15 @@ const (
 type GitspaceState string
 
 const (
-	GitspaceStateUnspecified GitspaceState = "unspecified"
-	GitspaceStateRunning     GitspaceState = "running"
-	GitspaceStateStopped     GitspaceState = "stopped"
-	GitspaceStateError       GitspaceState = "error"
-	GitspaceStateUnknown     GitspaceState = "unknown"
+	GitspaceStateUnspecified   GitspaceState = "unspecified"
+	GitspaceStateUninitialized GitspaceState = "uninitialized"
+	GitspaceStateStarting      GitspaceState = "starting"
+	GitspaceStateRunning       GitspaceState = "running"
+	GitspaceStateStopping      GitspaceState = "stopping"
+	GitspaceStateStopped       GitspaceState = "stopped"
+	GitspaceStateCleaning      GitspaceState = "cleaning"
+	GitspaceStateError         GitspaceState = "error"
+	GitspaceStateUnknown       GitspaceState = "unknown"
 )
 
 // GitspaceAction represents an action to perform on a gitspace
@@ -246,8 +439,15 @@ type GitspaceAction string
 const (
 	GitspaceActionStart GitspaceAction = "start"
 	GitspaceActionStop  GitspaceAction = "stop"
+	GitspaceActionReset GitspaceAction = "reset"
+
+	// GitspaceActionRestart is performed by the SDK as a stop followed by a start
+	GitspaceActionRestart GitspaceAction = "restart"
 )
 
+// gitspacePollInterval is how often RestartGitspace checks the gitspace state
+var gitspacePollInterval = 2 * time.Second
+
 // ListGitspacesOptions specifies the optional parameters for listing gitspaces
 type ListGitspacesOptions struct {
 	ListOptions
@@ -256,31 +456,22 @@ type ListGitspacesOptions struct {
 
 // ListGitspaces lists gitspaces with optional filtering
 func (s *GitspacesService) ListGitspaces(ctx context.Context, opt *ListGitspacesOptions) ([]*Gitspace, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+	if opt == nil {
+		opt = &ListGitspacesOptions{}
+	}
 
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	var gitspaces []*Gitspace
+	resp, err := s.client.performListRequestWithParams(ctx, "gitspaces", func(req httpRequest) {
+		s.client.buildQueryParams(req, &opt.ListOptions)
 		if opt.SpaceRef != nil {
 			req.SetQueryParam("space_ref", *opt.SpaceRef)
 		}
-	}
-
-	var gitspaces []*Gitspace
-	req.SetSuccessResult(&gitspaces)
-
-	resp, err := req.Get("gitspaces")
+	}, &gitspaces)
 	if err != nil {
-		return nil, &Response{Response: resp}, err
-	}
-
-	if err := s.client.checkResponse(resp); err != nil {
-		return nil, &Response{Response: resp}, err
+		return nil, resp, err
 	}
 
-	response := &Response{Response: resp}
-	s.client.parsePaginationHeaders(response)
-
-	return gitspaces, response, nil
+	return gitspaces, resp, nil
 }
 
 // CreateGitspaceRequest represents a request to create a new gitspace
@@ -327,9 +518,13 @@ type GitspaceActionRequest struct {
 	Action GitspaceAction `json:"action,omitempty"`
 }
 
-// ActionOnGitspace performs an action on a gitspace (start/stop)
+// ActionOnGitspace performs an action on a gitspace (start/stop/reset/restart)
 func (s *GitspacesService) ActionOnGitspace(ctx context.Context, identifier string, action GitspaceAction) (*Gitspace, *Response, error) {
-	path := fmt.Sprintf("gitspaces/%s/actions", url.PathEscape(identifier))
+	if action == GitspaceActionRestart {
+		return s.RestartGitspace(ctx, identifier)
+	}
+
+	path := fmt.Sprintf("gitspaces/%s/action", url.PathEscape(identifier))
 	req := &GitspaceActionRequest{Action: action}
 
 	var gitspace Gitspace
@@ -340,6 +535,65 @@ func (s *GitspacesService) ActionOnGitspace(ctx context.Context, identifier stri
 	return &gitspace, resp, nil
 }
 
+// RestartGitspace stops a gitspace, waits until it has left the running and
+// stopping states, and starts it again. This also recovers a gitspace that
+// is stuck in the error state.
+func (s *GitspacesService) RestartGitspace(ctx context.Context, identifier string) (*Gitspace, *Response, error) {
+	gitspace, resp, err := s.ActionOnGitspace(ctx, identifier, GitspaceActionStop)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for gitspace.State != nil && (*gitspace.State == GitspaceStateRunning || *gitspace.State == GitspaceStateStopping) {
+		select {
+		case <-ctx.Done():
+			return nil, resp, ctx.Err()
+		case <-s.client.clock.After(gitspacePollInterval):
+		}
+
+		gitspace, resp, err = s.FindGitspace(ctx, identifier)
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	return s.ActionOnGitspace(ctx, identifier, GitspaceActionStart)
+}
+
+// ErrGitspaceFailed is returned by WaitForGitspaceState when the gitspace enters the error state
+var ErrGitspaceFailed = errors.New("gitspace entered the error state")
+
+// WaitForGitspaceState polls a gitspace until it reaches the target state.
+// It returns ErrGitspaceFailed along with the gitspace if it enters the error
+// state instead, or the context error once ctx is done. A non-positive
+// pollInterval uses the default of two seconds.
+func (s *GitspacesService) WaitForGitspaceState(ctx context.Context, identifier string, target GitspaceState, pollInterval time.Duration) (*Gitspace, error) {
+	if pollInterval <= 0 {
+		pollInterval = gitspacePollInterval
+	}
+
+	for {
+		gitspace, _, err := s.FindGitspace(ctx, identifier)
+		if err != nil {
+			return nil, err
+		}
+		if gitspace.State != nil {
+			switch *gitspace.State {
+			case target:
+				return gitspace, nil
+			case GitspaceStateError:
+				return gitspace, ErrGitspaceFailed
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return gitspace, ctx.Err()
+		case <-s.client.clock.After(pollInterval):
+		}
+	}
+}
+
 // GitspaceEvent represents an event in gitspace lifecycle
 type GitspaceEvent struct {
 	ID        *int64  `json:"id,omitempty"`
@@ -356,12 +610,14 @@ type ListGitspaceEventsOptions struct {
 
 // ListGitspaceEvents lists events for a specific gitspace
 func (s *GitspacesService) ListGitspaceEvents(ctx context.Context, identifier string, opt *ListGitspaceEventsOptions) ([]*GitspaceEvent, *Response, error) {
+	if opt == nil {
+		opt = &ListGitspaceEventsOptions{}
+	}
+
 	path := fmt.Sprintf("gitspaces/%s/events", url.PathEscape(identifier))
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx)
 
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
-	}
+	s.client.buildQueryParams(req, &opt.ListOptions)
 
 	var events []*GitspaceEvent
 	req.SetSuccessResult(&events)
@@ -456,7 +712,7 @@ func (s *InfraProvidersService) CreateInfraProvider(ctx context.Context, spaceRe
 
 // GetInfraProvider retrieves a specific infrastructure provider by identifier
 func (s *InfraProvidersService) GetInfraProvider(ctx context.Context, spaceRef, identifier string) (*InfraProvider, *Response, error) {
-	path := fmt.Sprintf("spaces/%s/infra-providers/%s", url.PathEscape(spaceRef), identifier)
+	path := fmt.Sprintf("spaces/%s/infra-providers/%s", url.PathEscape(spaceRef), url.PathEscape(identifier))
 	var infraProvider InfraProvider
 	resp, err := s.client.Get(ctx, path, &infraProvider)
 	if err != nil {
@@ -0,0 +1,265 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestUpdateWebhookDisable tests that Enabled=false is sent when disabling a webhook
+func TestUpdateWebhookDisable(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identifier":"ci","enabled":false}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	_, _, err = client.Webhooks.UpdateWebhook(context.Background(), "space/repo", "ci", &UpdateWebhookOptions{
+		Enabled: Ptr(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdateWebhook returned error: %v", err)
+	}
+
+	if enabled, ok := body["enabled"]; !ok || enabled != false {
+		t.Errorf("Expected enabled=false to be sent, got %v", body)
+	}
+}
+
+// TestCreateWebhookTriggers tests that triggers are sent, including unknown ones, and malformed ones rejected
+func TestCreateWebhookTriggers(t *testing.T) {
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/repos/{repo}/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body CreateWebhookOptions
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Webhook{Identifier: body.Identifier, Triggers: body.Triggers})
+	})
+
+	client := NewTestClient(mux)
+
+	webhook, _, err := client.Webhooks.CreateWebhook(context.Background(), "space/repo", &CreateWebhookOptions{
+		Identifier: Ptr("ci"),
+		Triggers:   []WebhookTrigger{WebhookTriggerBranchCreated, WebhookTriggerPullReqCommentCreated},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook returned error: %v", err)
+	}
+	if len(webhook.Triggers) != 2 || webhook.Triggers[1] != WebhookTriggerPullReqCommentCreated {
+		t.Errorf("Unexpected triggers %v", webhook.Triggers)
+	}
+
+	if _, _, err := client.Webhooks.CreateWebhook(context.Background(), "space/repo", &CreateWebhookOptions{
+		Triggers: []WebhookTrigger{"branch_renamed"},
+	}); err != nil {
+		t.Errorf("Expected a well-formed unknown trigger to be sent, got %v", err)
+	}
+
+	for _, trigger := range []WebhookTrigger{"", "Branch Created", "branch-created", "branch__created"} {
+		if _, _, err := client.Webhooks.CreateWebhook(context.Background(), "space/repo", &CreateWebhookOptions{
+			Triggers: []WebhookTrigger{trigger},
+		}); err == nil {
+			t.Errorf("Expected error for malformed trigger %q", trigger)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected malformed webhooks not to be sent, got %d requests", requests)
+	}
+}
+
+// TestSyncSecretsToRepos tests creating, updating on conflict, and reporting failures per repository
+func TestSyncSecretsToRepos(t *testing.T) {
+	var mu sync.Mutex
+	var updated []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/repos/{repo}/secrets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PathValue("repo") {
+		case "space/existing":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message": "secret already exists"}`))
+		case "space/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "boom"}`))
+		default:
+			w.Write([]byte(`{"identifier": "token"}`))
+		}
+	})
+	mux.HandleFunc("PATCH /api/v1/secrets/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		updated = append(updated, r.PathValue("ref"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identifier": "token"}`))
+	})
+
+	client := NewTestClient(mux)
+
+	failures := client.Secrets.SyncSecretsToRepos(context.Background(),
+		[]string{"space/new", "space/existing", "space/broken"},
+		[]*CreateSecretOptions{{Identifier: Ptr("token"), Data: Ptr("s3cr3t")}},
+	)
+
+	if len(failures) != 1 || failures["space/broken"] == nil {
+		t.Errorf("Expected only space/broken to fail, got %v", failures)
+	}
+	if len(updated) != 1 || updated[0] != "space/existing/token" {
+		t.Errorf("Expected the existing secret to be updated, got %v", updated)
+	}
+}
+
+// TestRestartGitspace tests that restart stops, waits for the stopped state and starts again
+func TestRestartGitspace(t *testing.T) {
+	defer func(interval time.Duration) { gitspacePollInterval = interval }(gitspacePollInterval)
+	gitspacePollInterval = time.Millisecond
+
+	var actions []GitspaceAction
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			var req GitspaceActionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			actions = append(actions, req.Action)
+			if req.Action == GitspaceActionStop {
+				json.NewEncoder(w).Encode(&Gitspace{State: Ptr(GitspaceStateStopping)})
+				return
+			}
+			json.NewEncoder(w).Encode(&Gitspace{State: Ptr(GitspaceStateStarting)})
+		case http.MethodGet:
+			polls++
+			state := GitspaceStateStopping
+			if polls > 1 {
+				state = GitspaceStateStopped
+			}
+			json.NewEncoder(w).Encode(&Gitspace{State: Ptr(state)})
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	gitspace, _, err := client.Gitspaces.ActionOnGitspace(context.Background(), "dev", GitspaceActionRestart)
+	if err != nil {
+		t.Fatalf("ActionOnGitspace returned error: %v", err)
+	}
+
+	if len(actions) != 2 || actions[0] != GitspaceActionStop || actions[1] != GitspaceActionStart {
+		t.Errorf("Expected stop then start, got %v", actions)
+	}
+	if polls != 2 {
+		t.Errorf("Expected 2 polls, got %d", polls)
+	}
+	if *gitspace.State != GitspaceStateStarting {
+		t.Errorf("Expected starting state, got %s", *gitspace.State)
+	}
+}
+
+// TestWaitForGitspaceState tests waiting for a target state and failing on the error state
+func TestWaitForGitspaceState(t *testing.T) {
+	states := []GitspaceState{GitspaceStateStarting, GitspaceStateStarting, GitspaceStateRunning, GitspaceStateError}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Gitspace{State: Ptr(states[polls])})
+		polls++
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	gitspace, err := client.Gitspaces.WaitForGitspaceState(ctx, "dev", GitspaceStateRunning, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForGitspaceState returned error: %v", err)
+	}
+	if *gitspace.State != GitspaceStateRunning || polls != 3 {
+		t.Errorf("Expected running after 3 polls, got %s after %d", *gitspace.State, polls)
+	}
+
+	_, err = client.Gitspaces.WaitForGitspaceState(ctx, "dev", GitspaceStateStopped, time.Millisecond)
+	if !errors.Is(err, ErrGitspaceFailed) {
+		t.Errorf("Expected ErrGitspaceFailed, got %v", err)
+	}
+}
+
+func TestCopySecretAndDelete(t *testing.T) {
+	var created map[string]any
+	deleted := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/secrets/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.PathValue("ref"); got != "team/deploy-key" {
+			t.Errorf("secret ref = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"identifier":"deploy-key","description":"deploy"}`))
+	})
+	mux.HandleFunc("POST /api/v1/spaces/{space}/secrets", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.PathValue("space"); got != "org" {
+			t.Errorf("target space = %q, want org", got)
+		}
+		json.NewDecoder(r.Body).Decode(&created)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"identifier":"deploy-key","description":"deploy"}`))
+	})
+	mux.HandleFunc("DELETE /api/v1/secrets/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := NewTestClient(mux)
+
+	secret, _, err := client.Secrets.CopySecretAndDelete(context.Background(), "team/deploy-key", "org", "s3cr3t")
+	if err != nil {
+		t.Fatalf("CopySecretAndDelete returned error: %v", err)
+	}
+	if *secret.Identifier != "deploy-key" {
+		t.Errorf("identifier = %q", *secret.Identifier)
+	}
+	if created["identifier"] != "deploy-key" || created["description"] != "deploy" || created["data"] != "s3cr3t" {
+		t.Errorf("create body = %v", created)
+	}
+	if !deleted {
+		t.Error("original secret was not deleted")
+	}
+
+	if _, _, err := client.Secrets.CopySecretAndDelete(context.Background(), "deploy-key", "org", "x"); err == nil {
+		t.Error("expected error for a reference without a space")
+	}
+}
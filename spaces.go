@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // SpacesService handles communication with space related methods
@@ -61,36 +62,29 @@ func (s *SpacesService) GetSpace(ctx context.Context, spaceRef string) (*Space,
 	return &space, resp, nil
 }
 
+// GetSpaceOrNil is like GetSpace but returns a nil Space without an error when the space does not exist
+func (s *SpacesService) GetSpaceOrNil(ctx context.Context, spaceRef string) (*Space, *Response, error) {
+	return notFoundAsNil(s.GetSpace(ctx, spaceRef))
+}
+
 // ListSpaces lists spaces
 func (s *SpacesService) ListSpaces(ctx context.Context, opt *ListSpacesOptions) ([]*Space, *Response, error) {
-	var spaces []*Space
-
-	req := s.client.client.R().SetContext(ctx)
-
-	// Add query parameters if options provided
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	if opt == nil {
+		opt = &ListSpacesOptions{}
+	}
 
+	var spaces []*Space
+	resp, err := s.client.performListRequestWithParams(ctx, "spaces", func(req httpRequest) {
+		s.client.buildQueryParams(req, &opt.ListOptions)
 		if opt.Recursive != nil {
 			req.SetQueryParam("recursive", fmt.Sprintf("%t", *opt.Recursive))
 		}
-	}
-
-	req.SetSuccessResult(&spaces)
-
-	resp, err := req.Get("spaces")
+	}, &spaces)
 	if err != nil {
-		return nil, &Response{Response: resp}, err
-	}
-
-	if err := s.client.checkResponse(resp); err != nil {
-		return nil, &Response{Response: resp}, err
+		return nil, resp, err
 	}
 
-	response := &Response{Response: resp}
-	s.client.parsePaginationHeaders(response)
-
-	return spaces, response, nil
+	return spaces, resp, nil
 }
 
 // CreateSpace creates a new space
@@ -103,11 +97,45 @@ func (s *SpacesService) CreateSpace(ctx context.Context, opt *CreateSpaceOptions
 	return &space, resp, nil
 }
 
-// UpdateSpace updates a space
+// UpdateSpace updates a space. The update endpoint only accepts the
+// description, so IsPublic is applied through UpdateSpacePublicAccess and the
+// description is only sent when it is set or IsPublic is not. When the
+// visibility change fails after the description was applied, the error is a
+// *PartialUpdateError.
 func (s *SpacesService) UpdateSpace(ctx context.Context, spaceRef string, opt *UpdateSpaceOptions) (*Space, *Response, error) {
-	path := fmt.Sprintf("spaces/%s", url.PathEscape(spaceRef))
+	if opt == nil {
+		opt = &UpdateSpaceOptions{}
+	}
+
+	var (
+		space   *Space
+		resp    *Response
+		err     error
+		applied []string
+	)
+	if opt.Description != nil || opt.IsPublic == nil {
+		path := fmt.Sprintf("spaces/%s", url.PathEscape(spaceRef))
+		space = &Space{}
+		resp, err = s.client.Patch(ctx, path, opt, space)
+		if err != nil {
+			return nil, resp, err
+		}
+		applied = append(applied, "description")
+	}
+	if opt.IsPublic != nil {
+		space, resp, err = s.UpdateSpacePublicAccess(ctx, spaceRef, *opt.IsPublic)
+		if err != nil {
+			return nil, resp, partialUpdate(applied, "is_public", err)
+		}
+	}
+	return space, resp, nil
+}
+
+// UpdateSpacePublicAccess makes a space public or private
+func (s *SpacesService) UpdateSpacePublicAccess(ctx context.Context, spaceRef string, isPublic bool) (*Space, *Response, error) {
+	path := fmt.Sprintf("spaces/%s/public-access", url.PathEscape(spaceRef))
 	var space Space
-	resp, err := s.client.Patch(ctx, path, opt, &space)
+	resp, err := s.client.Post(ctx, path, &UpdatePublicAccessOptions{IsPublic: isPublic}, &space)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -136,24 +164,27 @@ func (s *SpacesService) DeleteSpace(ctx context.Context, spaceRef string, delete
 
 // ListRepositories lists repositories in a space
 func (s *SpacesService) ListRepositories(ctx context.Context, spaceRef string, opt *ListRepositoriesOptions) ([]*Repository, *Response, error) {
+	if opt == nil {
+		opt = &ListRepositoriesOptions{}
+	}
+
 	path := fmt.Sprintf("spaces/%s/repos", url.PathEscape(spaceRef))
 	var repositories []*Repository
 
-	req := s.client.client.R().SetContext(ctx)
+	req := s.client.newRequest(ctx)
 
-	// Add query parameters if options provided
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
-
-		if opt.Sort != nil {
-			req.SetQueryParam("sort", *opt.Sort)
-		}
-		if opt.Order != nil {
-			req.SetQueryParam("order", *opt.Order)
-		}
-		if opt.Query != nil {
-			req.SetQueryParam("query", *opt.Query)
-		}
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.Sort != nil {
+		req.SetQueryParam("sort", *opt.Sort)
+	}
+	if opt.Order != nil {
+		req.SetQueryParam("order", *opt.Order)
+	}
+	if opt.Query != nil {
+		req.SetQueryParam("query", *opt.Query)
+	}
+	if opt.Recursive != nil {
+		req.SetQueryParam("recursive", fmt.Sprintf("%t", *opt.Recursive))
 	}
 
 	req.SetSuccessResult(&repositories)
@@ -173,3 +204,99 @@ func (s *SpacesService) ListRepositories(ctx context.Context, spaceRef string, o
 
 	return repositories, response, nil
 }
+
+// ListRepositoriesPaginated lists repositories in a space and returns a Page that can fetch the next page
+func (s *SpacesService) ListRepositoriesPaginated(ctx context.Context, spaceRef string, opt *ListRepositoriesOptions) (*Page[Repository], error) {
+	var base ListRepositoriesOptions
+	if opt != nil {
+		base = *opt
+	}
+	return fetchPage(ctx, base.Page, func(ctx context.Context, page int) ([]*Repository, *Response, error) {
+		o := base
+		o.Page = Ptr(page)
+		return s.ListRepositories(ctx, spaceRef, &o)
+	})
+}
+
+// SpacePrincipal is a principal with access to a space
+type SpacePrincipal struct {
+	Principal *PrincipalInfo `json:"principal,omitempty"`
+	// Role is the membership role; nil for service accounts, which belong to the space
+	Role    *SpaceRole     `json:"role,omitempty"`
+	AddedBy *PrincipalInfo `json:"added_by,omitempty"`
+}
+
+// ListSpacePrincipalsOptions specifies options for listing the principals of a space
+type ListSpacePrincipalsOptions struct {
+	ListOptions
+	// Type limits the result to members (PrincipalTypeUser) or service accounts
+	Type *PrincipalType `url:"-"`
+}
+
+// ListSpacePrincipals lists the principals with access to a space: its
+// members and its service accounts. Members are paginated and searched by
+// the API. Service accounts are not paginated, so they are filtered by Query
+// client-side and returned with the first page only.
+func (s *SpacesService) ListSpacePrincipals(ctx context.Context, spaceRef string, opt *ListSpacePrincipalsOptions) ([]*SpacePrincipal, *Response, error) {
+	if opt == nil {
+		opt = &ListSpacePrincipalsOptions{}
+	}
+	if opt.Type != nil && *opt.Type != PrincipalTypeUser && *opt.Type != PrincipalTypeServiceAccount {
+		return nil, nil, fmt.Errorf("invalid space principal type %q", *opt.Type)
+	}
+
+	var principals []*SpacePrincipal
+	var response *Response
+	if opt.Type == nil || *opt.Type == PrincipalTypeUser {
+		path := fmt.Sprintf("spaces/%s/members", url.PathEscape(spaceRef))
+		resp, err := s.client.performListRequest(ctx, path, &opt.ListOptions, &principals)
+		if err != nil {
+			return nil, resp, err
+		}
+		response = resp
+	}
+
+	firstPage := opt.Page == nil || *opt.Page <= 1
+	if (opt.Type == nil && firstPage) || (opt.Type != nil && *opt.Type == PrincipalTypeServiceAccount) {
+		path := fmt.Sprintf("spaces/%s/service-accounts", url.PathEscape(spaceRef))
+		var accounts []*Principal
+		resp, err := s.client.Get(ctx, path, &accounts)
+		if err != nil {
+			return nil, resp, err
+		}
+		if response == nil {
+			response = resp
+		}
+
+		query := ""
+		if opt.Query != nil {
+			query = strings.ToLower(*opt.Query)
+		}
+		for _, account := range accounts {
+			if query != "" && !principalMatches(account, query) {
+				continue
+			}
+			principals = append(principals, &SpacePrincipal{
+				Principal: &PrincipalInfo{
+					ID:          account.ID,
+					UID:         account.UID,
+					DisplayName: account.DisplayName,
+					Email:       account.Email,
+					Type:        Ptr(string(PrincipalTypeServiceAccount)),
+				},
+			})
+		}
+	}
+
+	return principals, response, nil
+}
+
+// principalMatches reports whether the UID or display name of p contains the lower-case query
+func principalMatches(p *Principal, query string) bool {
+	for _, field := range []*string{p.UID, p.DisplayName} {
+		if field != nil && strings.Contains(strings.ToLower(*field), query) {
+			return true
+		}
+	}
+	return false
+}
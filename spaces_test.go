@@ -0,0 +1,101 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// TestListSpacePrincipals tests combining members and service accounts with type and query filters
+func TestListSpacePrincipals(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/spaces/{space}/members", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"principal": {"id": 3, "uid": "carol", "type": "user"}, "role": "reader"}]`))
+			return
+		}
+		w.Header().Set("x-next-page", "2")
+		w.Write([]byte(`[{"principal": {"id": 1, "uid": "alice", "type": "user"}, "role": "space_owner"}]`))
+	})
+	mux.HandleFunc("GET /api/v1/spaces/{space}/service-accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 7, "uid": "ci-bot", "display_name": "CI"}, {"id": 8, "uid": "deployer"}]`))
+	})
+
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	principals, resp, err := client.Spaces.ListSpacePrincipals(ctx, "space", nil)
+	if err != nil {
+		t.Fatalf("ListSpacePrincipals returned error: %v", err)
+	}
+	if len(principals) != 3 || *principals[0].Role != SpaceRoleOwner || principals[1].Role != nil || *principals[1].Principal.Type != "serviceaccount" {
+		t.Errorf("Unexpected principals %+v", principals)
+	}
+	if resp.NextPage == nil || *resp.NextPage != 2 {
+		t.Errorf("Expected member pagination to be kept, got %v", resp.NextPage)
+	}
+
+	principals, _, err = client.Spaces.ListSpacePrincipals(ctx, "space", &ListSpacePrincipalsOptions{ListOptions: ListOptions{Page: Ptr(2)}})
+	if err != nil {
+		t.Fatalf("ListSpacePrincipals returned error: %v", err)
+	}
+	if len(principals) != 1 || *principals[0].Principal.UID != "carol" {
+		t.Errorf("Expected only members on the second page, got %+v", principals)
+	}
+
+	principals, _, err = client.Spaces.ListSpacePrincipals(ctx, "space", &ListSpacePrincipalsOptions{
+		ListOptions: ListOptions{Query: Ptr("ci")},
+		Type:        Ptr(PrincipalTypeServiceAccount),
+	})
+	if err != nil {
+		t.Fatalf("ListSpacePrincipals returned error: %v", err)
+	}
+	if len(principals) != 1 || *principals[0].Principal.UID != "ci-bot" {
+		t.Errorf("Expected only the matching service account, got %+v", principals)
+	}
+
+	if _, _, err := client.Spaces.ListSpacePrincipals(ctx, "space", &ListSpacePrincipalsOptions{Type: Ptr(PrincipalTypeService)}); err == nil {
+		t.Error("Expected error for the service principal type")
+	}
+}
+
+// TestUpdateSpacePartialFailure tests that a failed visibility change after the description was applied is reported as such
+func TestUpdateSpacePartialFailure(t *testing.T) {
+	patches := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("PATCH /api/v1/spaces/{space}", func(w http.ResponseWriter, r *http.Request) {
+		patches++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"description":"new"}`))
+	})
+	mux.HandleFunc("POST /api/v1/spaces/{space}/public-access", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"forbidden"}`))
+	})
+	client := NewTestClient(mux)
+	ctx := context.Background()
+
+	_, _, err := client.Spaces.UpdateSpace(ctx, "space", &UpdateSpaceOptions{Description: Ptr("new"), IsPublic: Ptr(true)})
+	var partial *PartialUpdateError
+	if !errors.As(err, &partial) || len(partial.Applied) != 1 || partial.Applied[0] != "description" || partial.Failed != "is_public" {
+		t.Fatalf("Expected description applied and is_public failed, got %v", err)
+	}
+
+	_, _, err = client.Spaces.UpdateSpace(ctx, "space", &UpdateSpaceOptions{IsPublic: Ptr(true)})
+	if errors.As(err, &partial) || err == nil {
+		t.Errorf("Expected a plain API error when nothing was applied, got %v", err)
+	}
+	if patches != 1 {
+		t.Errorf("Expected 1 PATCH, got %d", patches)
+	}
+}
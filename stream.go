@@ -0,0 +1,114 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// contextBody ties a streamed response body to a context: once ctx is done
+// the body is closed, so a read blocked on a hung server returns, and reads
+// report the context error instead of the resulting connection error.
+type contextBody struct {
+	ctx  context.Context
+	body io.ReadCloser
+	stop func() bool
+}
+
+// newContextBody wraps body so that canceling ctx aborts reads from it
+func newContextBody(ctx context.Context, body io.ReadCloser) io.ReadCloser {
+	return &contextBody{
+		ctx:  ctx,
+		body: body,
+		stop: context.AfterFunc(ctx, func() { body.Close() }),
+	}
+}
+
+func (b *contextBody) Read(p []byte) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := b.body.Read(p)
+	if err != nil && b.ctx.Err() != nil {
+		return n, b.ctx.Err()
+	}
+	return n, err
+}
+
+func (b *contextBody) Close() error {
+	b.stop()
+	return b.body.Close()
+}
+
+// streamJSONArray decodes a JSON array element by element and calls fn for
+// each one, so the full list is never held in memory. The array may be the
+// top-level value or the member named field of a top-level object. Decoding
+// stops at the first error returned by fn, which is returned unchanged.
+func streamJSONArray[T any](r io.Reader, field string, fn func(*T) error) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decode stream: %w", err)
+	}
+	switch tok {
+	case json.Delim('['):
+		return streamArrayElements(dec, fn)
+	case json.Delim('{'):
+	default:
+		return fmt.Errorf("decode stream: unexpected token %v", tok)
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decode stream: %w", err)
+		}
+		if key != field {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("decode stream: %w", err)
+			}
+			continue
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decode stream: %w", err)
+		}
+		if tok == nil {
+			continue
+		}
+		if tok != json.Delim('[') {
+			return fmt.Errorf("decode stream: field %q is not an array", field)
+		}
+		if err := streamArrayElements(dec, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamArrayElements decodes the remaining elements of an opened JSON array
+func streamArrayElements[T any](dec *json.Decoder, fn func(*T) error) error {
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("decode stream: %w", err)
+		}
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("decode stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStreamCommits tests that commits are delivered one by one and that the callback can stop the stream
+func TestStreamCommits(t *testing.T) {
+	bodies := map[string]string{
+		"object": `{"rename_details":[],"commits":[{"sha":"a"},{"sha":"b"},{"sha":"c"}],"total_commits":3}`,
+		"array":  `[{"sha":"a"},{"sha":"b"},{"sha":"c"}]`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+			if err != nil {
+				t.Fatalf("NewClient returned error: %v", err)
+			}
+
+			var shas []string
+			_, err = client.Repositories.StreamCommits(context.Background(), "space/repo", nil, func(c *Commit) error {
+				shas = append(shas, *c.SHA)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("StreamCommits returned error: %v", err)
+			}
+			if len(shas) != 3 || shas[0] != "a" || shas[2] != "c" {
+				t.Errorf("Expected commits a, b, c, got %v", shas)
+			}
+
+			errStop := errors.New("stop")
+			shas = nil
+			_, err = client.Repositories.StreamCommits(context.Background(), "space/repo", nil, func(c *Commit) error {
+				shas = append(shas, *c.SHA)
+				if len(shas) == 2 {
+					return errStop
+				}
+				return nil
+			})
+			if !errors.Is(err, errStop) {
+				t.Fatalf("Expected stop error, got %v", err)
+			}
+			if len(shas) != 2 {
+				t.Errorf("Expected 2 commits before stopping, got %d", len(shas))
+			}
+		})
+	}
+}
+
+// TestStreamCommitsError tests that API errors are returned as ErrorResponse
+func TestStreamCommitsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"repository not found"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	_, err = client.Repositories.StreamCommits(context.Background(), "space/repo", nil, func(*Commit) error { return nil })
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Message != "repository not found" {
+		t.Fatalf("Expected ErrorResponse, got %v", err)
+	}
+}
@@ -8,6 +8,11 @@ package gitness
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 )
 
 // SystemService handles communication with system related methods
@@ -41,3 +46,201 @@ func (s *SystemService) GetSystemConfig(ctx context.Context) (*SystemConfig, *Re
 	}
 	return &config, resp, nil
 }
+
+// Health represents the health of a Gitness instance
+type Health struct {
+	Healthy    bool `json:"healthy"`
+	StatusCode int  `json:"status_code"`
+}
+
+// GetHealth checks whether the Gitness instance is up and serving requests.
+// An unhealthy instance is reported through Health rather than an error; an
+// error is only returned when the instance cannot be reached.
+func (s *SystemService) GetHealth(ctx context.Context) (*Health, *Response, error) {
+	fullURL := s.client.buildFullURL("system/health")
+	resp, err := s.client.newRequest(ctx).Get(fullURL)
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	health := &Health{
+		Healthy:    resp.StatusCode == http.StatusOK,
+		StatusCode: resp.StatusCode,
+	}
+	return health, &Response{Response: resp}, nil
+}
+
+// VersionInfo represents the build version of a Gitness instance
+type VersionInfo struct {
+	Version *string `json:"version,omitempty"`
+}
+
+// GetVersion retrieves the build version of the Gitness instance
+func (s *SystemService) GetVersion(ctx context.Context) (*VersionInfo, *Response, error) {
+	fullURL := s.client.buildFullURL("system/version")
+	resp, err := s.client.newRequest(ctx).Get(fullURL)
+	if err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	// The version is served either as a bare JSON string or as an object
+	var info VersionInfo
+	var version string
+	body := resp.Bytes()
+	switch {
+	case json.Unmarshal(body, &version) == nil:
+		info.Version = &version
+	case json.Unmarshal(body, &info) == nil && info.Version != nil:
+	default:
+		version = strings.TrimSpace(resp.String())
+		info.Version = &version
+	}
+
+	return &info, &Response{Response: resp}, nil
+}
+
+// Feature identifies a server capability that is not available on every
+// Gitness release. The SDK does not ship a release table; callers register
+// the minimum version of each feature they depend on with WithFeature.
+type Feature string
+
+// WithFeature records minVersion, such as "v3.1.0", as the first server
+// release providing feature
+func WithFeature(feature Feature, minVersion string) ClientOptionFunc {
+	return func(c *Client) error {
+		v, ok := parseServerVersion(minVersion)
+		if !ok {
+			return fmt.Errorf("invalid version %q for feature %q", minVersion, feature)
+		}
+		if c.features == nil {
+			c.features = make(map[Feature]serverVersion)
+		}
+		c.features[feature] = v
+		return nil
+	}
+}
+
+// serverVersion is a parsed major.minor.patch release number
+type serverVersion [3]int
+
+// parseServerVersion parses versions such as "v3.1.0" or "3.1.0-rc1+abc"
+func parseServerVersion(v string) (serverVersion, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+
+	var sv serverVersion
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return sv, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return sv, false
+		}
+		sv[i] = n
+	}
+	return sv, true
+}
+
+// atLeast reports whether v is the same as or newer than min
+func (v serverVersion) atLeast(min serverVersion) bool {
+	for i := range v {
+		if v[i] != min[i] {
+			return v[i] > min[i]
+		}
+	}
+	return true
+}
+
+// versionCall is a GetVersion request shared by concurrent callers
+type versionCall struct {
+	done    chan struct{}
+	version serverVersion
+	err     error
+}
+
+// serverVersion returns the instance version, fetching it on first use.
+// Concurrent callers share one request, which runs outside versionMu; a
+// failed fetch is not cached.
+func (c *Client) serverVersion(ctx context.Context) (serverVersion, error) {
+	c.versionMu.Lock()
+	if c.version != nil {
+		v := *c.version
+		c.versionMu.Unlock()
+		return v, nil
+	}
+	call := c.versionCall
+	if call == nil {
+		call = &versionCall{done: make(chan struct{})}
+		c.versionCall = call
+		go c.fetchServerVersion(call)
+	}
+	c.versionMu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.version, call.err
+	case <-ctx.Done():
+		return serverVersion{}, ctx.Err()
+	}
+}
+
+// fetchServerVersion completes call, caching the version on success. It uses
+// a detached context so one caller's cancellation does not fail the others.
+func (c *Client) fetchServerVersion(call *versionCall) {
+	call.version, call.err = c.getServerVersion(context.Background())
+
+	c.versionMu.Lock()
+	if call.err == nil {
+		c.version = &call.version
+	}
+	c.versionCall = nil
+	c.versionMu.Unlock()
+	close(call.done)
+}
+
+// getServerVersion fetches and parses the instance version
+func (c *Client) getServerVersion(ctx context.Context) (serverVersion, error) {
+	info, _, err := c.System.GetVersion(ctx)
+	if err != nil {
+		return serverVersion{}, err
+	}
+	if info.Version == nil {
+		return serverVersion{}, fmt.Errorf("server did not report a version")
+	}
+
+	v, ok := parseServerVersion(*info.Version)
+	if !ok {
+		return serverVersion{}, fmt.Errorf("unrecognized server version %q", *info.Version)
+	}
+	return v, nil
+}
+
+// SupportsContext reports whether the Gitness instance provides the given
+// feature. The server version is fetched once and cached on the client.
+func (c *Client) SupportsContext(ctx context.Context, feature Feature) (bool, error) {
+	min, ok := c.features[feature]
+	if !ok {
+		return false, fmt.Errorf("unknown feature %q, register it with WithFeature", feature)
+	}
+
+	v, err := c.serverVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	return v.atLeast(min), nil
+}
+
+// Supports reports whether the Gitness instance provides the given feature.
+// It returns false when the server version cannot be determined.
+func (c *Client) Supports(feature Feature) bool {
+	ok, err := c.SupportsContext(context.Background(), feature)
+	return err == nil && ok
+}
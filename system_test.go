@@ -0,0 +1,70 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSupportsCachesServerVersion tests feature checks against the reported server version
+func TestSupportsCachesServerVersion(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/system/version" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`"v3.0.4"`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL+"/"),
+		WithFeature("gitspaces", "v3.0.0"),
+		WithFeature("rebase", "3.1"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !client.Supports("gitspaces") {
+				t.Errorf("Expected gitspaces to be supported")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if client.Supports("rebase") {
+		t.Errorf("Expected rebase to be unsupported")
+	}
+	if _, err := client.SupportsContext(context.Background(), "unknown"); err == nil {
+		t.Errorf("Expected an error for an unregistered feature")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected version to be fetched once, got %d calls", got)
+	}
+}
+
+// TestWithFeatureInvalidVersion tests that an unparseable minimum version is rejected
+func TestWithFeatureInvalidVersion(t *testing.T) {
+	if _, err := NewClient("test-token", WithFeature("labels", "latest")); err == nil {
+		t.Fatal("Expected an error for an invalid feature version")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imroc/req/v3"
+)
+
+// testBaseURL is the base URL used by clients created with NewTestClient
+const testBaseURL = "http://gitness.test/"
+
+// NewTestClient returns a client whose requests are served in-process by
+// handler, without opening any network connection. Handlers see the same
+// paths as a real server, for example /api/v1/repos/space%2Frepo.
+func NewTestClient(handler http.Handler, options ...ClientOptionFunc) *Client {
+	options = append([]ClientOptionFunc{WithBaseURL(testBaseURL)}, options...)
+	c, err := NewClient("test-token", options...)
+	if err != nil {
+		panic("gitness: NewTestClient: " + err.Error())
+	}
+
+	c.client.GetTransport().WrapRoundTripFunc(func(http.RoundTripper) req.HttpRoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, r)
+			resp := recorder.Result()
+			resp.Request = r
+			return resp, nil
+		}
+	})
+	return c
+}
+
+// TestNewTestClient tests that requests are routed to the in-process handler
+func TestNewTestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/repos/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Expected bearer token, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Repository{Path: Ptr(r.PathValue("repo"))})
+	})
+
+	client := NewTestClient(mux)
+
+	repo, _, err := client.Repositories.GetRepository(context.Background(), "space/repo")
+	if err != nil {
+		t.Fatalf("GetRepository returned error: %v", err)
+	}
+	if repo.Path == nil || *repo.Path != "space/repo" {
+		t.Errorf("Expected path space/repo, got %v", repo.Path)
+	}
+
+	_, _, err = client.Spaces.GetSpace(context.Background(), "space")
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 ErrorResponse for unhandled route, got %v", err)
+	}
+}
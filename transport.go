@@ -0,0 +1,113 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+
+	"github.com/imroc/req/v3"
+)
+
+// httpDoer creates the requests services send. Services go through it rather
+// than the req client directly so that request building can be wrapped or
+// recorded in tests. Sending still returns *req.Response, so an httpDoer is
+// tied to req/v3; replacing the HTTP library would also mean changing every
+// caller that reads the response.
+type httpDoer interface {
+	newRequest(ctx context.Context) httpRequest
+}
+
+// httpRequest is the subset of request building and sending the services use
+type httpRequest interface {
+	SetQueryParam(key, value string) httpRequest
+	AddQueryParams(key string, values ...string) httpRequest
+	SetHeader(key, value string) httpRequest
+	SetContentType(contentType string) httpRequest
+	SetBody(body any) httpRequest
+	SetBodyJsonMarshal(v any) httpRequest
+	SetSuccessResult(result any) httpRequest
+	DisableAutoReadResponse() httpRequest
+
+	Send(method, url string) (*req.Response, error)
+	Get(url string) (*req.Response, error)
+	Head(url string) (*req.Response, error)
+	Post(url string) (*req.Response, error)
+	Put(url string) (*req.Response, error)
+	Patch(url string) (*req.Response, error)
+	Delete(url string) (*req.Response, error)
+}
+
+// reqDoer is the req/v3 backed httpDoer
+type reqDoer struct {
+	client *req.Client
+}
+
+func (d reqDoer) newRequest(ctx context.Context) httpRequest {
+	return reqRequest{d.client.R().SetContext(ctx)}
+}
+
+// reqRequest adapts *req.Request to httpRequest
+type reqRequest struct {
+	r *req.Request
+}
+
+func (r reqRequest) SetQueryParam(key, value string) httpRequest {
+	r.r.SetQueryParam(key, value)
+	return r
+}
+
+func (r reqRequest) AddQueryParams(key string, values ...string) httpRequest {
+	r.r.AddQueryParams(key, values...)
+	return r
+}
+
+func (r reqRequest) SetHeader(key, value string) httpRequest {
+	r.r.SetHeader(key, value)
+	return r
+}
+
+func (r reqRequest) SetContentType(contentType string) httpRequest {
+	r.r.SetContentType(contentType)
+	return r
+}
+
+func (r reqRequest) SetBody(body any) httpRequest {
+	r.r.SetBody(body)
+	return r
+}
+
+func (r reqRequest) SetBodyJsonMarshal(v any) httpRequest {
+	r.r.SetBodyJsonMarshal(v)
+	return r
+}
+
+func (r reqRequest) SetSuccessResult(result any) httpRequest {
+	r.r.SetSuccessResult(result)
+	return r
+}
+
+func (r reqRequest) DisableAutoReadResponse() httpRequest {
+	r.r.DisableAutoReadResponse()
+	r.r.SetContext(withoutResponseCache(r.r.Context()))
+	return r
+}
+
+func (r reqRequest) Send(method, url string) (*req.Response, error) {
+	return r.r.Send(method, url)
+}
+
+func (r reqRequest) Get(url string) (*req.Response, error)    { return r.r.Get(url) }
+func (r reqRequest) Head(url string) (*req.Response, error)   { return r.r.Head(url) }
+func (r reqRequest) Post(url string) (*req.Response, error)   { return r.r.Post(url) }
+func (r reqRequest) Put(url string) (*req.Response, error)    { return r.r.Put(url) }
+func (r reqRequest) Patch(url string) (*req.Response, error)  { return r.r.Patch(url) }
+func (r reqRequest) Delete(url string) (*req.Response, error) { return r.r.Delete(url) }
+
+// newRequest returns a request bound to ctx from the client's httpDoer
+func (c *Client) newRequest(ctx context.Context) httpRequest {
+	return c.doer.newRequest(ctx)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/imroc/req/v3"
+)
+
+// recordingDoer wraps another httpDoer and records the URLs fetched with GET
+type recordingDoer struct {
+	next httpDoer
+	gets []string
+}
+
+func (d *recordingDoer) newRequest(ctx context.Context) httpRequest {
+	return recordingRequest{httpRequest: d.next.newRequest(ctx), doer: d}
+}
+
+type recordingRequest struct {
+	httpRequest
+	doer *recordingDoer
+}
+
+func (r recordingRequest) Get(url string) (*req.Response, error) {
+	r.doer.gets = append(r.doer.gets, url)
+	return r.httpRequest.Get(url)
+}
+
+func TestHTTPDoer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/system/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	client := NewTestClient(mux)
+
+	if _, ok := client.doer.(reqDoer); !ok {
+		t.Fatalf("default doer = %T, want reqDoer", client.doer)
+	}
+
+	doer := &recordingDoer{next: client.doer}
+	client.doer = doer
+
+	health, _, err := client.System.GetHealth(context.Background())
+	if err != nil {
+		t.Fatalf("GetHealth returned error: %v", err)
+	}
+	if !health.Healthy {
+		t.Errorf("Healthy = false, want true")
+	}
+	if len(doer.gets) != 1 || doer.gets[0] != testBaseURL+"api/v1/system/health" {
+		t.Errorf("recorded GETs = %v", doer.gets)
+	}
+}
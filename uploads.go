@@ -8,6 +8,7 @@ package gitness
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 )
@@ -26,14 +27,49 @@ type Upload struct {
 	Created   *Time   `json:"created,omitempty"`
 }
 
+// ErrUploadTooLarge is returned by CreateUpload when the file exceeds the client's upload limit
+var ErrUploadTooLarge = errors.New("upload exceeds maximum size")
+
+// WithMaxUploadSize sets a size limit CreateUpload checks before contacting
+// the server, for callers that know their instance's limit. The check is off
+// by default; a size of 0 disables it again.
+func WithMaxUploadSize(size int64) ClientOptionFunc {
+	return func(c *Client) error {
+		if size < 0 {
+			return fmt.Errorf("max upload size must not be negative, got %d", size)
+		}
+		c.maxUploadSize = size
+		return nil
+	}
+}
+
+// validateUpload rejects uploads the server would refuse
+func (s *UploadService) validateUpload(fileName string, fileSize int64) error {
+	if fileName == "" {
+		return errors.New("upload file name must not be empty")
+	}
+	if fileSize <= 0 {
+		return fmt.Errorf("upload %q: file size must be positive, got %d", fileName, fileSize)
+	}
+	if limit := s.client.maxUploadSize; limit > 0 && fileSize > limit {
+		return fmt.Errorf("%w: %q is %d bytes, limit is %d bytes", ErrUploadTooLarge, fileName, fileSize, limit)
+	}
+	return nil
+}
+
 // CreateUploadRequest represents the request to create an upload session
 type CreateUploadRequest struct {
 	FileName *string `json:"file_name,omitempty"`
 	FileSize *int64  `json:"file_size,omitempty"`
 }
 
-// CreateUpload creates an upload session
+// CreateUpload creates an upload session. When WithMaxUploadSize is set, the
+// file size is checked against that limit before any request is made.
 func (s *UploadService) CreateUpload(ctx context.Context, repoPath string, fileName string, fileSize int64) (*Upload, *Response, error) {
+	if err := s.validateUpload(fileName, fileSize); err != nil {
+		return nil, nil, err
+	}
+
 	path := fmt.Sprintf("repos/%s/uploads", url.PathEscape(repoPath))
 
 	payload := &CreateUploadRequest{
@@ -51,7 +87,7 @@ func (s *UploadService) CreateUpload(ctx context.Context, repoPath string, fileN
 
 // GetUpload retrieves upload information
 func (s *UploadService) GetUpload(ctx context.Context, repoPath, fileRef string) (*Upload, *Response, error) {
-	path := fmt.Sprintf("repos/%s/uploads/%s", url.PathEscape(repoPath), fileRef)
+	path := fmt.Sprintf("repos/%s/uploads/%s", url.PathEscape(repoPath), url.PathEscape(fileRef))
 	var upload Upload
 	resp, err := s.client.Get(ctx, path, &upload)
 	if err != nil {
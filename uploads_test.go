@@ -0,0 +1,54 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// TestCreateUploadValidation tests that invalid or oversized uploads are rejected without a request
+func TestCreateUploadValidation(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"file_name":"build.zip"}`))
+	})
+
+	ctx := context.Background()
+	client := NewTestClient(handler, WithMaxUploadSize(10<<20))
+
+	if _, _, err := client.Upload.CreateUpload(ctx, "space/repo", "build.zip", 0); err == nil {
+		t.Error("Expected error for zero size")
+	}
+	if _, _, err := client.Upload.CreateUpload(ctx, "space/repo", "", 1); err == nil {
+		t.Error("Expected error for empty file name")
+	}
+	if _, _, err := client.Upload.CreateUpload(ctx, "space/repo", "build.zip", 10<<20+1); !errors.Is(err, ErrUploadTooLarge) {
+		t.Errorf("Expected ErrUploadTooLarge, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no requests, got %d", requests)
+	}
+
+	if _, _, err := client.Upload.CreateUpload(ctx, "space/repo", "build.zip", 10<<20); err != nil {
+		t.Errorf("CreateUpload returned error: %v", err)
+	}
+
+	for _, client := range []*Client{NewTestClient(handler), NewTestClient(handler, WithMaxUploadSize(0))} {
+		if _, _, err := client.Upload.CreateUpload(ctx, "space/repo", "build.zip", 100<<20); err != nil {
+			t.Errorf("Expected no limit to allow upload, got %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
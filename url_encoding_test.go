@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -20,7 +21,7 @@ func TestURLEncodingForRepoPath(t *testing.T) {
 	var receivedPath string
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		receivedPath = r.URL.Path
+		receivedPath = r.URL.EscapedPath()
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode([]*Branch{})
@@ -58,7 +59,7 @@ func TestURLEncodingForBranchName(t *testing.T) {
 	var receivedPath string
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		receivedPath = r.URL.Path
+		receivedPath = r.URL.EscapedPath()
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(&Branch{})
@@ -89,3 +90,178 @@ func TestURLEncodingForBranchName(t *testing.T) {
 		t.Errorf("Path encoding is incorrect.\nExpected: %s\nReceived: %s", expectedPath, receivedPath)
 	}
 }
+
+// TestGetAndRequestBuilderShareURLConstruction tests that helper-based and R()-based methods build identical URLs
+func TestGetAndRequestBuilderShareURLConstruction(t *testing.T) {
+	var receivedPaths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPaths = append(receivedPaths, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/commits") {
+			w.Write([]byte(`{"commits":[],"total_commits":0}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/spaces") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// A base URL with a sub-path and a pasted API suffix must still resolve to the same endpoints
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/gitness/api/v1"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, _, err := client.Repositories.GetRepository(ctx, "ci/demo"); err != nil {
+		t.Fatalf("GetRepository returned error: %v", err)
+	}
+	if _, _, err := client.Repositories.ListCommits(ctx, "ci/demo", nil); err != nil {
+		t.Fatalf("ListCommits returned error: %v", err)
+	}
+	if _, _, err := client.Spaces.ListSpaces(ctx, nil); err != nil {
+		t.Fatalf("ListSpaces returned error: %v", err)
+	}
+
+	expected := []string{
+		"/gitness/api/v1/repos/ci%2Fdemo",
+		"/gitness/api/v1/repos/ci%2Fdemo/commits",
+		"/gitness/api/v1/spaces",
+	}
+	if len(receivedPaths) != len(expected) {
+		t.Fatalf("Expected %d requests, got %v", len(expected), receivedPaths)
+	}
+	for i, want := range expected {
+		if receivedPaths[i] != want {
+			t.Errorf("Request %d: expected path %s, got %s", i, want, receivedPaths[i])
+		}
+	}
+}
+
+// TestURLEncodingForNestedRepoMethods tests that methods taking a space/repo path send it as a single escaped segment
+func TestURLEncodingForNestedRepoMethods(t *testing.T) {
+	var receivedPath, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	repoPath := "space/repo"
+
+	tests := []struct {
+		name         string
+		body         string
+		call         func() error
+		expectedPath string
+	}{
+		{"ListCommits", `[]`, func() error {
+			_, _, err := client.Repositories.ListCommits(ctx, repoPath, nil)
+			return err
+		}, "/api/v1/repos/space%2Frepo/commits"},
+		{"ListPaths", `{}`, func() error {
+			_, _, err := client.Repositories.ListPaths(ctx, repoPath, nil)
+			return err
+		}, "/api/v1/repos/space%2Frepo/paths"},
+		{"ListTags", `[]`, func() error {
+			_, _, err := client.Repositories.ListTags(ctx, repoPath, nil)
+			return err
+		}, "/api/v1/repos/space%2Frepo/tags"},
+		{"ListPullRequests", `[]`, func() error {
+			_, _, err := client.PullRequests.ListPullRequests(ctx, repoPath, nil)
+			return err
+		}, "/api/v1/repos/space%2Frepo/pullreq"},
+		{"ListPipelineExecutions", `[]`, func() error {
+			_, _, err := client.Pipelines.ListPipelineExecutions(ctx, repoPath, "build", nil)
+			return err
+		}, "/api/v1/repos/space%2Frepo/pipelines/build/executions"},
+		{"CreateExecution", `{}`, func() error {
+			_, _, err := client.Pipelines.CreateExecution(ctx, repoPath, "build", Ptr("main"))
+			return err
+		}, "/api/v1/repos/space%2Frepo/pipelines/build/executions"},
+		{"ListSpaceRepositories", `[]`, func() error {
+			_, _, err := client.Spaces.ListRepositories(ctx, "org/team", nil)
+			return err
+		}, "/api/v1/spaces/org%2Fteam/repos"},
+		{"GetTemplate", `{}`, func() error {
+			_, _, err := client.Templates.GetTemplate(ctx, "org/team", "build")
+			return err
+		}, "/api/v1/spaces/org%2Fteam/templates/build"},
+		{"GetUpload", `{}`, func() error {
+			_, _, err := client.Upload.GetUpload(ctx, repoPath, "image.png")
+			return err
+		}, "/api/v1/repos/space%2Frepo/uploads/image.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = tt.body
+			if err := tt.call(); err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+			if receivedPath != tt.expectedPath {
+				t.Errorf("Expected path %s, got %s", tt.expectedPath, receivedPath)
+			}
+		})
+	}
+}
+
+// TestURLEncodingForRefs tests that refs containing slashes are escaped in path segments and query parameters
+func TestURLEncodingForRefs(t *testing.T) {
+	var receivedPath, receivedQuery, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.EscapedPath()
+		receivedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	body = `{}`
+	if _, _, err := client.Repositories.GetBranch(ctx, "space/repo", "feature/foo"); err != nil {
+		t.Fatalf("GetBranch returned error: %v", err)
+	}
+	if want := "/api/v1/repos/space%2Frepo/branches/feature%2Ffoo"; receivedPath != want {
+		t.Errorf("GetBranch: expected path %s, got %s", want, receivedPath)
+	}
+
+	body = `[]`
+	if _, _, err := client.Repositories.ListCommits(ctx, "space/repo", &ListCommitsOptions{GitRef: Ptr("feature/foo")}); err != nil {
+		t.Fatalf("ListCommits returned error: %v", err)
+	}
+	if want := "git_ref=feature%2Ffoo"; receivedQuery != want {
+		t.Errorf("ListCommits: expected query %s, got %s", want, receivedQuery)
+	}
+
+	body = `{"commits":2,"files_changed":3}`
+	stats, _, err := client.Repositories.CompareRefs(ctx, "space/repo", "main", "feature/foo")
+	if err != nil {
+		t.Fatalf("CompareRefs returned error: %v", err)
+	}
+	if want := "/api/v1/repos/space%2Frepo/diff-stats/main...feature%2Ffoo"; receivedPath != want {
+		t.Errorf("CompareRefs: expected path %s, got %s", want, receivedPath)
+	}
+	if stats.Commits == nil || *stats.Commits != 2 {
+		t.Errorf("Expected 2 commits, got %v", stats.Commits)
+	}
+}
@@ -49,13 +49,34 @@ type CreateTokenOptions struct {
 	Lifetime   *int64  `json:"lifetime,omitempty"`
 }
 
+// SpaceRole represents the role of a member in a space
+type SpaceRole string
+
+// SpaceRole constants
+const (
+	SpaceRoleOwner       SpaceRole = "space_owner"
+	SpaceRoleContributor SpaceRole = "contributor"
+	SpaceRoleExecutor    SpaceRole = "executor"
+	SpaceRoleReader      SpaceRole = "reader"
+)
+
+// IsValid reports whether r is a role known to Gitness
+func (r SpaceRole) IsValid() bool {
+	switch r {
+	case SpaceRoleOwner, SpaceRoleContributor, SpaceRoleExecutor, SpaceRoleReader:
+		return true
+	default:
+		return false
+	}
+}
+
 // UserMembership represents user's membership in spaces
 type UserMembership struct {
-	SpaceID   *int64  `json:"space_id,omitempty"`
-	SpacePath *string `json:"space_path,omitempty"`
-	Role      *string `json:"role,omitempty"`
-	AddedBy   *int64  `json:"added_by,omitempty"`
-	Added     *Time   `json:"added,omitempty"`
+	SpaceID   *int64     `json:"space_id,omitempty"`
+	SpacePath *string    `json:"space_path,omitempty"`
+	Role      *SpaceRole `json:"role,omitempty"`
+	AddedBy   *int64     `json:"added_by,omitempty"`
+	Added     *Time      `json:"added,omitempty"`
 }
 
 // ListPublicKeysOptions specifies options for listing public keys
@@ -71,12 +92,25 @@ type ListTokensOptions struct {
 
 // UserFavorite represents a user's favorite resource
 type UserFavorite struct {
-	ResourceID   *int64  `json:"resource_id,omitempty"`
-	ResourceType *string `json:"resource_type,omitempty"`
-	ResourcePath *string `json:"resource_path,omitempty"`
-	Added        *Time   `json:"added,omitempty"`
+	ResourceID   *int64                `json:"resource_id,omitempty"`
+	ResourceType *FavoriteResourceType `json:"resource_type,omitempty"`
+	ResourcePath *string               `json:"resource_path,omitempty"`
+	Added        *Time                 `json:"added,omitempty"`
 }
 
+// FavoriteResourceType represents the type of a favorited resource
+type FavoriteResourceType string
+
+// FavoriteResourceType constants
+const (
+	FavoriteResourceTypeRepository FavoriteResourceType = "REPOSITORY"
+	FavoriteResourceTypeSpace      FavoriteResourceType = "SPACE"
+	FavoriteResourceTypePipeline   FavoriteResourceType = "PIPELINE"
+	FavoriteResourceTypeGitspace   FavoriteResourceType = "GITSPACE"
+	FavoriteResourceTypeConnector  FavoriteResourceType = "CONNECTOR"
+	FavoriteResourceTypeTemplate   FavoriteResourceType = "TEMPLATE"
+)
+
 // GetCurrentUser retrieves the current authenticated user
 func (s *UsersService) GetCurrentUser(ctx context.Context) (*User, *Response, error) {
 	var user User
@@ -100,21 +134,21 @@ func (s *UsersService) GetUser(ctx context.Context, userUID string) (*User, *Res
 
 // ListUserKeys lists user's public keys
 func (s *UsersService) ListUserKeys(ctx context.Context, opt *ListPublicKeysOptions) ([]*PublicKey, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
+	if opt == nil {
+		opt = &ListPublicKeysOptions{}
+	}
 
-	// Add query parameters if options provided
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	req := s.client.newRequest(ctx)
 
-		if opt.Usage != nil {
-			req.SetQueryParam("usage", *opt.Usage)
-		}
+	s.client.buildQueryParams(req, &opt.ListOptions)
+	if opt.Usage != nil {
+		req.SetQueryParam("usage", *opt.Usage)
 	}
 
 	var keys []*PublicKey
 	req.SetSuccessResult(&keys)
 
-	resp, err := req.Get("user/keys")
+	resp, err := req.Get(s.client.buildFullURL("user/keys"))
 	if err != nil {
 		return nil, &Response{Response: resp}, err
 	}
@@ -159,17 +193,18 @@ func (s *UsersService) DeleteUserKey(ctx context.Context, keyID string) (*Respon
 
 // ListUserTokens lists user's personal access tokens
 func (s *UsersService) ListUserTokens(ctx context.Context, opt *ListTokensOptions) ([]*PersonalAccessToken, *Response, error) {
-	req := s.client.client.R().SetContext(ctx)
-
-	// Add query parameters if options provided
-	if opt != nil {
-		buildQueryParams(req, &opt.ListOptions)
+	if opt == nil {
+		opt = &ListTokensOptions{}
 	}
 
+	req := s.client.newRequest(ctx)
+
+	s.client.buildQueryParams(req, &opt.ListOptions)
+
 	var tokens []*PersonalAccessToken
 	req.SetSuccessResult(&tokens)
 
-	resp, err := req.Get("user/tokens")
+	resp, err := req.Get(s.client.buildFullURL("user/tokens"))
 	if err != nil {
 		return nil, &Response{Response: resp}, err
 	}
@@ -204,24 +239,56 @@ func (s *UsersService) DeleteUserToken(ctx context.Context, tokenID string) (*Re
 // ListUserMemberships lists user's space memberships
 func (s *UsersService) ListUserMemberships(ctx context.Context) ([]*UserMembership, *Response, error) {
 	var memberships []*UserMembership
-	resp, err := s.client.Get(ctx, "user/memberships", &memberships)
+	resp, err := s.client.performListRequest(ctx, "user/memberships", nil, &memberships)
 	if err != nil {
 		return nil, resp, err
 	}
 	return memberships, resp, nil
 }
 
-// ListUserFavorites lists user's favorite resources
-func (s *UsersService) ListUserFavorites(ctx context.Context) ([]*UserFavorite, *Response, error) {
-	var favorites []*UserFavorite
-	resp, err := s.client.Get(ctx, "user/favorite", &favorites)
+// ListUserFavoritesOptions specifies options for listing user's favorites
+type ListUserFavoritesOptions struct {
+	ResourceType *FavoriteResourceType `url:"resource_type,omitempty"`
+}
+
+// ListUserFavorites lists user's favorite resources. When ResourceType is
+// set, it is sent to the server and the result is also filtered client-side,
+// so the filter holds even where the server ignores it.
+func (s *UsersService) ListUserFavorites(ctx context.Context, opt *ListUserFavoritesOptions) ([]*UserFavorite, *Response, error) {
+	req := s.client.newRequest(ctx)
+	if opt != nil && opt.ResourceType != nil {
+		req.SetQueryParam("resource_type", string(*opt.ResourceType))
+	}
+
+	resp, err := req.Get(s.client.buildFullURL("user/favorite"))
 	if err != nil {
-		return nil, resp, err
+		return nil, &Response{Response: resp}, err
 	}
-	return favorites, resp, nil
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	var favorites []*UserFavorite
+	if err := s.client.decodeResponse(resp, &favorites); err != nil {
+		return nil, &Response{Response: resp}, err
+	}
+
+	if opt != nil && opt.ResourceType != nil {
+		filtered := favorites[:0]
+		for _, favorite := range favorites {
+			if favorite.ResourceType != nil && *favorite.ResourceType == *opt.ResourceType {
+				filtered = append(filtered, favorite)
+			}
+		}
+		favorites = filtered
+	}
+	return favorites, &Response{Response: resp}, nil
 }
 
 // AddUserFavorite adds a resource to user's favorites
+//
+// Deprecated: Use AddFavorite, which sends the resource type the API requires.
 func (s *UsersService) AddUserFavorite(ctx context.Context, resourceID int64) (*UserFavorite, *Response, error) {
 	path := fmt.Sprintf("user/favorite/%d", resourceID)
 	var favorite UserFavorite
@@ -233,8 +300,79 @@ func (s *UsersService) AddUserFavorite(ctx context.Context, resourceID int64) (*
 }
 
 // RemoveUserFavorite removes a resource from user's favorites
+//
+// Deprecated: Use RemoveFavorite, which sends the resource type the API requires.
 func (s *UsersService) RemoveUserFavorite(ctx context.Context, resourceID int64) (*Response, error) {
 	path := fmt.Sprintf("user/favorite/%d", resourceID)
 	resp, err := s.client.Delete(ctx, path, nil)
 	return resp, err
 }
+
+// FavoriteOptions specifies the resource to add to the user's favorites
+type FavoriteOptions struct {
+	ResourceID   *int64                `json:"resource_id,omitempty"`
+	ResourceType *FavoriteResourceType `json:"resource_type,omitempty"`
+}
+
+// AddFavorite adds a resource of the given type to the user's favorites
+func (s *UsersService) AddFavorite(ctx context.Context, resourceType FavoriteResourceType, resourceID int64) (*UserFavorite, *Response, error) {
+	opt := &FavoriteOptions{ResourceID: Ptr(resourceID), ResourceType: Ptr(resourceType)}
+	var favorite UserFavorite
+	resp, err := s.client.Post(ctx, "user/favorite", opt, &favorite)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &favorite, resp, nil
+}
+
+// RemoveFavorite removes a resource of the given type from the user's favorites
+func (s *UsersService) RemoveFavorite(ctx context.Context, resourceType FavoriteResourceType, resourceID int64) (*Response, error) {
+	path := fmt.Sprintf("user/favorite/%d", resourceID)
+	resp, err := s.client.newRequest(ctx).
+		SetQueryParam("resource_type", string(resourceType)).
+		Delete(s.client.buildFullURL(path))
+	if err != nil {
+		return &Response{Response: resp}, err
+	}
+
+	if err := s.client.checkResponse(resp); err != nil {
+		return &Response{Response: resp}, err
+	}
+	return &Response{Response: resp}, nil
+}
+
+// AddRepoFavorite adds a repository to the user's favorites by its path
+func (s *UsersService) AddRepoFavorite(ctx context.Context, repoPath string) (*UserFavorite, *Response, error) {
+	repository, resp, err := s.client.Repositories.GetRepository(ctx, repoPath)
+	if err != nil {
+		return nil, resp, err
+	}
+	if repository.ID == nil {
+		return nil, resp, fmt.Errorf("repository %q has no ID", repoPath)
+	}
+	return s.AddFavorite(ctx, FavoriteResourceTypeRepository, *repository.ID)
+}
+
+// AddSpaceFavorite adds a space to the user's favorites by its reference
+func (s *UsersService) AddSpaceFavorite(ctx context.Context, spaceRef string) (*UserFavorite, *Response, error) {
+	space, resp, err := s.client.Spaces.GetSpace(ctx, spaceRef)
+	if err != nil {
+		return nil, resp, err
+	}
+	if space.ID == nil {
+		return nil, resp, fmt.Errorf("space %q has no ID", spaceRef)
+	}
+	return s.AddFavorite(ctx, FavoriteResourceTypeSpace, *space.ID)
+}
+
+// AddPipelineFavorite adds a pipeline to the user's favorites by its repository and identifier
+func (s *UsersService) AddPipelineFavorite(ctx context.Context, repoPath, pipelineID string) (*UserFavorite, *Response, error) {
+	pipeline, resp, err := s.client.Pipelines.GetPipeline(ctx, repoPath, pipelineID)
+	if err != nil {
+		return nil, resp, err
+	}
+	if pipeline.ID == nil {
+		return nil, resp, fmt.Errorf("pipeline %q has no ID", pipelineID)
+	}
+	return s.AddFavorite(ctx, FavoriteResourceTypePipeline, *pipeline.ID)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025-2025 All rights reserved.
+//
+// The original source code is licensed under the Apache License 2.0.
+//
+// You may review the terms of both licenses in the LICENSE file.
+
+package gitness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAddRepoFavorite tests that the repository ID is resolved and sent with its resource type
+func TestAddRepoFavorite(t *testing.T) {
+	var favorite FavoriteOptions
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"id":42,"path":"space/repo"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/user/favorite":
+			if err := json.NewDecoder(r.Body).Decode(&favorite); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(&favorite)
+		default:
+			t.Errorf("Unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	result, _, err := client.Users.AddRepoFavorite(context.Background(), "space/repo")
+	if err != nil {
+		t.Fatalf("AddRepoFavorite returned error: %v", err)
+	}
+
+	if *favorite.ResourceID != 42 || *favorite.ResourceType != FavoriteResourceTypeRepository {
+		t.Errorf("Unexpected favorite request: id=%d type=%s", *favorite.ResourceID, *favorite.ResourceType)
+	}
+	if *result.ResourceType != FavoriteResourceTypeRepository {
+		t.Errorf("Expected repository favorite, got %s", *result.ResourceType)
+	}
+}
+
+// TestRemoveFavorite tests that the resource type is sent as a query parameter
+func TestRemoveFavorite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/user/favorite/42" {
+			t.Errorf("Unexpected %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("resource_type"); got != "SPACE" {
+			t.Errorf("Expected resource_type SPACE, got %q", got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, err := client.Users.RemoveFavorite(context.Background(), FavoriteResourceTypeSpace, 42); err != nil {
+		t.Fatalf("RemoveFavorite returned error: %v", err)
+	}
+}
+
+// TestListUserFavoritesByType tests that favorites are filtered by resource type
+func TestListUserFavoritesByType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("resource_type"); got != "REPOSITORY" {
+			t.Errorf("Expected resource_type REPOSITORY, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"resource_id":1,"resource_type":"REPOSITORY"},{"resource_id":2,"resource_type":"SPACE"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	favorites, _, err := client.Users.ListUserFavorites(context.Background(), &ListUserFavoritesOptions{
+		ResourceType: Ptr(FavoriteResourceTypeRepository),
+	})
+	if err != nil {
+		t.Fatalf("ListUserFavorites returned error: %v", err)
+	}
+
+	if len(favorites) != 1 || *favorites[0].ResourceID != 1 {
+		t.Errorf("Expected only the repository favorite, got %d favorites", len(favorites))
+	}
+}
+
+// TestListUserMembershipsRole tests that membership roles decode to SpaceRole values
+func TestListUserMembershipsRole(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/user/memberships" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"space_path":"team","role":"space_owner"},{"space_path":"other","role":"admin"}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	memberships, _, err := client.Users.ListUserMemberships(context.Background())
+	if err != nil {
+		t.Fatalf("ListUserMemberships returned error: %v", err)
+	}
+
+	if len(memberships) != 2 {
+		t.Fatalf("Expected 2 memberships, got %d", len(memberships))
+	}
+	if role := memberships[0].Role; role == nil || *role != SpaceRoleOwner || !role.IsValid() {
+		t.Errorf("Expected valid role %s, got %v", SpaceRoleOwner, role)
+	}
+	if role := memberships[1].Role; role == nil || role.IsValid() {
+		t.Errorf("Expected unknown role to be kept but invalid, got %v", role)
+	}
+}